@@ -1,16 +1,24 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 
 	"github.com/turbot/terraform-provider-steampipecloud/steampipecloud"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return steampipecloud.Provider()
-		},
-	})
+	ctx := context.Background()
+
+	serverFactory, err := steampipecloud.ProviderServerFactory(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = tf5server.Serve("registry.terraform.io/turbot/steampipecloud", serverFactory)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
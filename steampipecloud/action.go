@@ -0,0 +1,52 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gokit "github.com/turbot/go-kit/types"
+)
+
+// parseActionTimeout parses the timeout of an action, e.g. "30m", defaulting to defaultProcessTimeout
+func parseActionTimeout(timeout types.String) (time.Duration, error) {
+	return parseProcessTimeout(timeout.ValueString())
+}
+
+// getActionOwner returns the handle of the organization if one is set, otherwise the handle of the user
+func getActionOwner(ctx context.Context, client *SteampipeClient, organization types.String) (userHandle, orgHandle string, err error) {
+	if orgHandle = organization.ValueString(); orgHandle != "" {
+		return "", orgHandle, nil
+	}
+	userHandle, r, err := getUserHandler(ctx, client)
+	if err != nil {
+		return "", "", fmt.Errorf("%s", actionErrorDetail(r, err))
+	}
+	return userHandle, "", nil
+}
+
+// actionErrorDetail returns the error body of an API response, or the error itself when there is no response,
+// e.g. when the request could not be sent. It is shared by the actions and ephemeral resources.
+func actionErrorDetail(r *http.Response, err error) string {
+	if r == nil {
+		return err.Error()
+	}
+	return decodeResponse(r)
+}
+
+// waitForActionProcess waits for a process started by an action to finish, and reports its outcome as progress
+func waitForActionProcess(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, processId string, timeout time.Duration, resp *action.InvokeResponse) error {
+	process, err := waitForProcess(ctx, client, userHandle, orgHandle, workspaceHandle, processId, timeout)
+	if err != nil {
+		return err
+	}
+	state := gokit.SafeString(process.State)
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Process %s %s", processId, state)})
+	if state != processStateCompleted {
+		return fmt.Errorf("process %s %s", processId, state)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type actionPipelineRun struct {
+	client *SteampipeClient
+}
+
+type actionPipelineRunModel struct {
+	Organization types.String `tfsdk:"organization"`
+	Workspace    types.String `tfsdk:"workspace"`
+	PipelineId   types.String `tfsdk:"pipeline_id"`
+	Wait         types.Bool   `tfsdk:"wait"`
+	Timeout      types.String `tfsdk:"timeout"`
+}
+
+// NewActionPipelineRun
+func NewActionPipelineRun() action.Action {
+	return &actionPipelineRun{}
+}
+
+func (a *actionPipelineRun) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pipeline_run"
+}
+
+func (a *actionPipelineRun) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a workspace pipeline and waits for the run to finish.",
+		Attributes: map[string]schema.Attribute{
+			"organization": schema.StringAttribute{
+				Optional: true,
+			},
+			"workspace": schema.StringAttribute{
+				Required: true,
+			},
+			"pipeline_id": schema.StringAttribute{
+				Required: true,
+			},
+			"wait": schema.BoolAttribute{
+				Optional: true,
+			},
+			"timeout": schema.StringAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (a *actionPipelineRun) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*SteampipeClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *SteampipeClient, got: %T", req.ProviderData))
+		return
+	}
+	a.client = client
+}
+
+func (a *actionPipelineRun) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data actionPipelineRunModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, err := parseActionTimeout(data.Timeout)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid timeout", err.Error())
+		return
+	}
+
+	workspaceHandle := data.Workspace.ValueString()
+	pipelineId := data.PipelineId.ValueString()
+	userHandle, orgHandle, err := getActionOwner(ctx, a.client, data.Organization)
+	if err != nil {
+		resp.Diagnostics.AddError("actionPipelineRunInvoke.getUserHandler error", err.Error())
+		return
+	}
+
+	processId, err := startWorkspacePipeline(ctx, a.client, userHandle, orgHandle, workspaceHandle, pipelineId)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error running workspace pipeline %s", pipelineId), err.Error())
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Pipeline %s started process %s", pipelineId, processId)})
+
+	if !data.Wait.IsNull() && !data.Wait.ValueBool() {
+		return
+	}
+	if err := waitForActionProcess(ctx, a.client, userHandle, orgHandle, workspaceHandle, processId, timeout, resp); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error running workspace pipeline %s", pipelineId), err.Error())
+	}
+}
@@ -0,0 +1,107 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gokit "github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+type actionWorkspaceModUpdate struct {
+	client *SteampipeClient
+}
+
+type actionWorkspaceModUpdateModel struct {
+	Organization    types.String `tfsdk:"organization"`
+	WorkspaceHandle types.String `tfsdk:"workspace_handle"`
+	ModAlias        types.String `tfsdk:"mod_alias"`
+}
+
+// NewActionWorkspaceModUpdate
+func NewActionWorkspaceModUpdate() action.Action {
+	return &actionWorkspaceModUpdate{}
+}
+
+func (a *actionWorkspaceModUpdate) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_mod_update"
+}
+
+func (a *actionWorkspaceModUpdate) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Updates a workspace mod to the latest version allowed by its constraint.",
+		Attributes: map[string]schema.Attribute{
+			"organization": schema.StringAttribute{
+				Optional: true,
+			},
+			"workspace_handle": schema.StringAttribute{
+				Required: true,
+			},
+			"mod_alias": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+func (a *actionWorkspaceModUpdate) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*SteampipeClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *SteampipeClient, got: %T", req.ProviderData))
+		return
+	}
+	a.client = client
+}
+
+func (a *actionWorkspaceModUpdate) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data actionWorkspaceModUpdateModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mod steampipe.WorkspaceMod
+	var r *http.Response
+
+	workspaceHandle := data.WorkspaceHandle.ValueString()
+	modAlias := data.ModAlias.ValueString()
+	userHandle, orgHandle, err := getActionOwner(ctx, a.client, data.Organization)
+	if err != nil {
+		resp.Diagnostics.AddError("actionWorkspaceModUpdateInvoke.getUserHandler error", err.Error())
+		return
+	}
+
+	// The mod is updated with its current constraint, so that it stays in line with steampipecloud_workspace_mod
+	if orgHandle == "" {
+		mod, r, err = a.client.APIClient.UserWorkspaceMods.Get(ctx, userHandle, workspaceHandle, modAlias).Execute()
+	} else {
+		mod, r, err = a.client.APIClient.OrgWorkspaceMods.Get(ctx, orgHandle, workspaceHandle, modAlias).Execute()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error reading workspace mod %s", modAlias), actionErrorDetail(r, err))
+		return
+	}
+	previousVersion := gokit.SafeString(mod.InstalledVersion)
+
+	updateReq := steampipe.UpdateWorkspaceModRequest{Constraint: gokit.SafeString(mod.Constraint)}
+	if orgHandle == "" {
+		mod, r, err = a.client.APIClient.UserWorkspaceMods.Update(ctx, userHandle, workspaceHandle, modAlias).Request(updateReq).Execute()
+	} else {
+		mod, r, err = a.client.APIClient.OrgWorkspaceMods.Update(ctx, orgHandle, workspaceHandle, modAlias).Request(updateReq).Execute()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error updating workspace mod %s", modAlias), actionErrorDetail(r, err))
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Mod %s updated from %s to %s, state %s", modAlias, previousVersion, gokit.SafeString(mod.InstalledVersion), gokit.SafeString(mod.State)),
+	})
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/turbot/go-kit/types"
 	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
@@ -30,6 +32,37 @@ func dataSourceProcess() *schema.Resource {
 				Required: true,
 				Computed: false,
 			},
+			"wait_for_terminal_state": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"timeout": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+					if _, err := parseProcessTimeout(v.(string)); err != nil {
+						return nil, []error{err}
+					}
+					return nil, nil
+				},
+			},
+			"output": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"error_message": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"duration": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"snapshot_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"identity_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -87,32 +120,31 @@ func dataSourceProcessRead(ctx context.Context, d *schema.ResourceData, meta int
 	processId := d.Get("process_id").(string)
 	workspace := d.Get("workspace").(string)
 
+	var actorHandle string
 	isUser, orgHandle := isUserConnection(d)
 	if isUser {
-		var actorHandle string
 		actorHandle, r, err = getUserHandler(ctx, client)
 		if err != nil {
 			return diag.Errorf("dataSourceProcessRead.getUserHandler error  %v", decodeResponse(r))
 		}
 		log.Printf("\n[DEBUG] Process get context-> identity:'%s'; workspace:'%s'; process:'%s'", actorHandle, workspace, processId)
-		// If a workspace is not passed we can assume that it is an identity process
-		if workspace == "" {
-			resp, r, err = client.APIClient.UserProcesses.Get(ctx, actorHandle, processId).Execute()
-		} else {
-			resp, r, err = client.APIClient.UserWorkspaceProcesses.Get(ctx, actorHandle, workspace, processId).Execute()
-		}
 	} else {
 		log.Printf("\n[DEBUG] Process get context-> identity:'%s'; workspace:'%s'; process:'%s'", orgHandle, workspace, processId)
-		//  If a workspace is not passed we can assume that it is an identity process
-		if workspace == "" {
-			resp, r, err = client.APIClient.OrgProcesses.Get(ctx, orgHandle, processId).Execute()
-		} else {
-			resp, r, err = client.APIClient.OrgWorkspaceProcesses.Get(ctx, orgHandle, workspace, processId).Execute()
-		}
 	}
 
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("%v", decodeResponse(r)))
+	// Processes which are still running are polled until they stop if asked to, e.g. for a pipeline run
+	// started in the same apply. If a workspace is not passed we can assume that it is an identity process.
+	if d.Get("wait_for_terminal_state").(bool) {
+		timeout, _ := parseProcessTimeout(d.Get("timeout").(string))
+		resp, err = waitForProcess(ctx, client, actorHandle, orgHandle, workspace, processId, timeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		resp, r, err = getProcess(ctx, client, actorHandle, orgHandle, workspace, processId)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("%v", decodeResponse(r)))
+		}
 	}
 
 	log.Printf("\n[DEBUG] Process Received: %v", resp)
@@ -132,9 +164,47 @@ func dataSourceProcessRead(ctx context.Context, d *schema.ResourceData, meta int
 		d.Set("updated_by", resp.UpdatedBy.Handle)
 	}
 	d.Set("version_id", resp.VersionId)
+	d.Set("duration", processDuration(resp))
 	d.Set("organization", orgHandle)
 	d.Set("workspace", workspace)
 	d.SetId(resp.Id)
 
+	// The output is only complete once the process has stopped running
+	if isProcessTerminal(types.SafeString(resp.State)) {
+		processLog, r, err := getProcessLog(ctx, client, actorHandle, orgHandle, workspace, processId)
+		if err != nil {
+			detail := err.Error()
+			if r != nil {
+				detail = decodeResponse(r)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Unable to get the log of process %s", processId),
+				Detail:   detail,
+			})
+			return diags
+		}
+		errorMessage, snapshotId := parseProcessLog(processLog)
+		d.Set("output", processLog)
+		d.Set("error_message", errorMessage)
+		d.Set("snapshot_id", snapshotId)
+	}
+
 	return diags
 }
+
+// processDuration returns how long a process ran for, or an empty string if it is still running
+func processDuration(process steampipe.SpProcess) string {
+	if !isProcessTerminal(types.SafeString(process.State)) {
+		return ""
+	}
+	createdAt, err := time.Parse(time.RFC3339, process.CreatedAt)
+	if err != nil {
+		return ""
+	}
+	updatedAt, err := time.Parse(time.RFC3339, process.UpdatedAt)
+	if err != nil {
+		return ""
+	}
+	return updatedAt.Sub(createdAt).String()
+}
@@ -0,0 +1,243 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func dataSourceProcesses() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceProcessesRead,
+		Schema: map[string]*schema.Schema{
+			"organization": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: false,
+			},
+			"workspace": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: false,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"pipeline_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"created_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"processes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"process_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"identity_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"workspace_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pipeline_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"duration": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_by": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_by": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// processFilter holds the conditions a process must meet to be returned by steampipecloud_processes.
+// Empty conditions match every process.
+type processFilter struct {
+	Type         string
+	State        string
+	PipelineId   string
+	CreatedAfter time.Time
+}
+
+func (f processFilter) matches(process steampipe.SpProcess) bool {
+	if f.Type != "" && process.Type != f.Type {
+		return false
+	}
+	if f.State != "" && types.SafeString(process.State) != f.State {
+		return false
+	}
+	if f.PipelineId != "" && types.SafeString(process.PipelineId) != f.PipelineId {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() {
+		createdAt, err := time.Parse(time.RFC3339, process.CreatedAt)
+		if err != nil || !createdAt.After(f.CreatedAfter) {
+			return false
+		}
+	}
+	return true
+}
+
+// where returns the where clause which filters the processes when they are listed, so that only the matching
+// processes are paged through. The processes are still matched against the filter once they are listed.
+func (f processFilter) where() string {
+	var conditions []string
+	if f.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("type = %s", sqlQuote(f.Type)))
+	}
+	if f.State != "" {
+		conditions = append(conditions, fmt.Sprintf("state = %s", sqlQuote(f.State)))
+	}
+	if f.PipelineId != "" {
+		conditions = append(conditions, fmt.Sprintf("pipeline_id = %s", sqlQuote(f.PipelineId)))
+	}
+	if !f.CreatedAfter.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at > %s", sqlQuote(f.CreatedAfter.Format(time.RFC3339))))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// filterProcesses returns the processes which match the filter, in the order they were listed
+func filterProcesses(processes []steampipe.SpProcess, filter processFilter) []steampipe.SpProcess {
+	var matched []steampipe.SpProcess
+	for _, process := range processes {
+		if filter.matches(process) {
+			matched = append(matched, process)
+		}
+	}
+	return matched
+}
+
+func dataSourceProcessesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	var actorHandle string
+	var r *http.Response
+	var err error
+
+	workspace := d.Get("workspace").(string)
+	filter := processFilter{
+		Type:       d.Get("type").(string),
+		State:      d.Get("state").(string),
+		PipelineId: d.Get("pipeline_id").(string),
+	}
+	if createdAfter := d.Get("created_after").(string); createdAfter != "" {
+		filter.CreatedAfter, err = time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return diag.Errorf("error parsing created_after %s: %v", createdAfter, err)
+		}
+	}
+
+	isUser, orgHandle := isUserConnection(d)
+	if isUser {
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return diag.Errorf("dataSourceProcessesRead.getUserHandler error  %v", decodeResponse(r))
+		}
+	}
+	log.Printf("\n[DEBUG] Processes list context-> identity:'%s%s'; workspace:'%s'", actorHandle, orgHandle, workspace)
+
+	// Workspace processes are filtered by the API, but identity processes cannot be, so every page of them is
+	// fetched and filtered here. If a workspace is not passed we can assume that identity processes are wanted.
+	processes, r, err := listProcesses(ctx, client, actorHandle, orgHandle, workspace, filter.where())
+	if err != nil {
+		if r == nil {
+			return diag.Errorf("error listing processes: %v", err)
+		}
+		return diag.Errorf("error listing processes: %v", decodeResponse(r))
+	}
+	processes = filterProcesses(processes, filter)
+	log.Printf("\n[DEBUG] Processes Received: %d", len(processes))
+
+	var items []map[string]interface{}
+	for _, process := range processes {
+		item := map[string]interface{}{
+			"process_id":   process.Id,
+			"identity_id":  types.SafeString(process.IdentityId),
+			"workspace_id": types.SafeString(process.WorkspaceId),
+			"pipeline_id":  types.SafeString(process.PipelineId),
+			"type":         process.Type,
+			"state":        types.SafeString(process.State),
+			"duration":     processDuration(process),
+			"created_at":   process.CreatedAt,
+			"updated_at":   process.UpdatedAt,
+			"version_id":   process.VersionId,
+		}
+		if process.CreatedBy != nil {
+			item["created_by"] = process.CreatedBy.Handle
+		}
+		if process.UpdatedBy != nil {
+			item["updated_by"] = process.UpdatedBy.Handle
+		}
+		items = append(items, item)
+	}
+	if err := d.Set("processes", items); err != nil {
+		return diag.Errorf("error setting processes: %v", err)
+	}
+	d.Set("organization", orgHandle)
+	d.Set("workspace", workspace)
+
+	id := actorHandle + orgHandle
+	if workspace != "" {
+		id = fmt.Sprintf("%s/%s", id, workspace)
+	}
+	d.SetId(id)
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// workspaceSnapshotDataSourceSchema returns the attributes of a snapshot exported by the snapshot data sources
+func workspaceSnapshotDataSourceSchema() map[string]*schema.Schema {
+	computed := func(valueType schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: valueType, Computed: true}
+	}
+	return map[string]*schema.Schema{
+		"workspace_snapshot_id": computed(schema.TypeString),
+		"identity_id":           computed(schema.TypeString),
+		"workspace_id":          computed(schema.TypeString),
+		"state":                 computed(schema.TypeString),
+		"visibility":            computed(schema.TypeString),
+		"dashboard_name":        computed(schema.TypeString),
+		"dashboard_title":       computed(schema.TypeString),
+		"schema_version":        computed(schema.TypeString),
+		"inputs":                computed(schema.TypeString),
+		"tags":                  computed(schema.TypeString),
+		"created_at":            computed(schema.TypeString),
+		"updated_at":            computed(schema.TypeString),
+		"expires_at":            computed(schema.TypeString),
+		"created_by":            computed(schema.TypeString),
+		"updated_by":            computed(schema.TypeString),
+		"version_id":            computed(schema.TypeInt),
+		"share_url":             computed(schema.TypeString),
+		"dashboard_url":         computed(schema.TypeString),
+		"data":                  computed(schema.TypeString),
+		"panels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func dataSourceWorkspaceSnapshot() *schema.Resource {
+	dataSourceSchema := workspaceSnapshotDataSourceSchema()
+	dataSourceSchema["organization"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: false,
+	}
+	dataSourceSchema["workspace_handle"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z0-9]{1,23}$`), "Handle must be between 1 and 23 characters, and may only contain alphanumeric characters."),
+	}
+	dataSourceSchema["workspace_snapshot_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"workspace_snapshot_id", "dashboard_name"},
+		ValidateFunc: validation.StringMatch(regexp.MustCompile("^"+snapshotIDRegex.String()+"$"), "Snapshot ID must be of the form snap_<id>_<suffix>."),
+	}
+	dataSourceSchema["dashboard_name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"workspace_snapshot_id", "dashboard_name"},
+	}
+	dataSourceSchema["include_data"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceWorkspaceSnapshotRead,
+		Schema:      dataSourceSchema,
+	}
+}
+
+func dataSourceWorkspaceSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	var diags diag.Diagnostics
+	var snapshot steampipe.WorkspaceSnapshot
+	var userHandle string
+	var r *http.Response
+	var err error
+
+	workspaceHandle := d.Get("workspace_handle").(string)
+	isUser, orgHandle := isUserConnection(d)
+	if isUser {
+		userHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return diag.Errorf("dataSourceWorkspaceSnapshotRead.getUserHandler error  %v", decodeResponse(r))
+		}
+	}
+
+	if snapshotId := d.Get("workspace_snapshot_id").(string); snapshotId != "" {
+		if isUser {
+			snapshot, r, err = client.APIClient.UserWorkspaceSnapshots.Get(ctx, userHandle, workspaceHandle, snapshotId).Execute()
+		} else {
+			snapshot, r, err = client.APIClient.OrgWorkspaceSnapshots.Get(ctx, orgHandle, workspaceHandle, snapshotId).Execute()
+		}
+		if err != nil {
+			return diag.Errorf("error getting workspace snapshot %s: %v", snapshotId, decodeResponse(r))
+		}
+	} else {
+		// The most recent snapshot of the dashboard is used, e.g. the last one taken by a scheduled pipeline
+		dashboardName := d.Get("dashboard_name").(string)
+		filter := workspaceSnapshotFilter{DashboardName: dashboardName}
+		snapshots, r, err := listWorkspaceSnapshots(ctx, client, orgHandle, workspaceHandle, filter.where())
+		if err != nil {
+			return diag.Errorf("error listing snapshots of workspace %s: %v", workspaceHandle, decodeResponse(r))
+		}
+		snapshots = filterWorkspaceSnapshots(snapshots, filter)
+		if len(snapshots) == 0 {
+			return diag.Errorf("no snapshots of dashboard %s found in workspace %s", dashboardName, workspaceHandle)
+		}
+		snapshot = snapshots[0]
+	}
+	log.Printf("\n[DEBUG] Snapshot: %s received for Workspace: %s", snapshot.Id, workspaceHandle)
+
+	item := flattenWorkspaceSnapshot(consoleURL(client), userHandle, orgHandle, workspaceHandle, snapshot)
+	if d.Get("include_data").(bool) {
+		data, r, err := downloadWorkspaceSnapshot(ctx, client, userHandle, orgHandle, workspaceHandle, snapshot.Id)
+		if err != nil {
+			return diag.Errorf("error downloading workspace snapshot %s: %v", snapshot.Id, decodeResponse(r))
+		}
+		item["data"], item["panels"] = flattenWorkspaceSnapshotData(data)
+	}
+	for key, value := range item {
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("error setting %s: %v", key, err)
+		}
+	}
+	d.Set("organization", orgHandle)
+
+	// If snapshot belongs to a workspace inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle/SnapshotID" otherwise "WorkspaceHandle/SnapshotID"
+	if strings.HasPrefix(snapshot.IdentityId, "o_") {
+		d.SetId(fmt.Sprintf("%s/%s/%s", orgHandle, workspaceHandle, snapshot.Id))
+	} else {
+		d.SetId(fmt.Sprintf("%s/%s", workspaceHandle, snapshot.Id))
+	}
+
+	return diags
+}
+
+// flattenWorkspaceSnapshot returns the attributes of a snapshot exported by the snapshot data sources, without
+// the snapshot data
+func flattenWorkspaceSnapshot(consoleURL, userHandle, orgHandle, workspaceHandle string, snapshot steampipe.WorkspaceSnapshot) map[string]interface{} {
+	shareURL, dashboardURL := snapshotURLs(consoleURL, userHandle, orgHandle, workspaceHandle, snapshot)
+	item := map[string]interface{}{
+		"workspace_snapshot_id": snapshot.Id,
+		"identity_id":           snapshot.IdentityId,
+		"workspace_id":          snapshot.WorkspaceId,
+		"state":                 types.SafeString(snapshot.State),
+		"visibility":            types.SafeString(snapshot.Visibility),
+		"dashboard_name":        snapshot.DashboardName,
+		"dashboard_title":       snapshot.DashboardTitle,
+		"schema_version":        snapshot.SchemaVersion,
+		"inputs":                "",
+		"tags":                  "",
+		"created_at":            snapshot.CreatedAt,
+		"updated_at":            types.SafeString(snapshot.UpdatedAt),
+		"expires_at":            types.SafeString(snapshot.ExpiresAt),
+		"created_by":            "",
+		"updated_by":            "",
+		"version_id":            int(snapshot.VersionId),
+		"share_url":             shareURL,
+		"dashboard_url":         dashboardURL,
+	}
+	if snapshot.Inputs != nil {
+		item["inputs"] = FormatJson(snapshot.Inputs)
+	}
+	if snapshot.Tags != nil {
+		item["tags"] = FormatJson(snapshot.Tags)
+	}
+	if snapshot.CreatedBy != nil {
+		item["created_by"] = snapshot.CreatedBy.Handle
+	}
+	if snapshot.UpdatedBy != nil {
+		item["updated_by"] = snapshot.UpdatedBy.Handle
+	}
+	return item
+}
+
+// flattenWorkspaceSnapshotData returns the snapshot data as JSON, along with the JSON data of each panel by name
+func flattenWorkspaceSnapshotData(data steampipe.WorkspaceSnapshotData) (string, map[string]interface{}) {
+	panels := map[string]interface{}{}
+	for name, panel := range data.Panels {
+		panels[name] = FormatJson(panel)
+	}
+	return FormatJson(data), panels
+}
+
+// downloadWorkspaceSnapshot gets the full data of a snapshot. The snapshot belongs to the organization if orgHandle
+// is set, otherwise to the user with userHandle.
+func downloadWorkspaceSnapshot(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, snapshotId string) (steampipe.WorkspaceSnapshotData, *http.Response, error) {
+	if orgHandle == "" {
+		return client.APIClient.UserWorkspaceSnapshots.Download(ctx, userHandle, workspaceHandle, snapshotId, "json").Execute()
+	}
+	return client.APIClient.OrgWorkspaceSnapshots.Download(ctx, orgHandle, workspaceHandle, snapshotId, "json").Execute()
+}
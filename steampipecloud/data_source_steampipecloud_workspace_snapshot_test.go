@@ -0,0 +1,157 @@
+package steampipecloud
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// test suites
+func TestAccUserWorkspaceSnapshotDataSource_Basic(t *testing.T) {
+	resourceName := "steampipecloud_workspace_snapshot.snapshot_1"
+	byIdDataSourceName := "data.steampipecloud_workspace_snapshot.by_id"
+	latestDataSourceName := "data.steampipecloud_workspace_snapshot.latest"
+	listDataSourceName := "data.steampipecloud_workspace_snapshots.tagged"
+	workspaceHandle := "workspace" + randomString(3)
+	dataFile := filepath.Join(t.TempDir(), "aws_s3_bucket_dashboard.sps")
+	if err := os.WriteFile(dataFile, []byte(testSnapshotData), 0600); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspaceSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserWorkspaceSnapshotDataSourceConfig(workspaceHandle, dataFile),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(byIdDataSourceName, "workspace_snapshot_id", resourceName, "workspace_snapshot_id"),
+					resource.TestCheckResourceAttr(byIdDataSourceName, "dashboard_name", "aws_insights.dashboard.aws_s3_bucket_dashboard"),
+					resource.TestCheckResourceAttr(byIdDataSourceName, "data", ""),
+					resource.TestCheckResourceAttrPair(latestDataSourceName, "workspace_snapshot_id", resourceName, "workspace_snapshot_id"),
+					resource.TestMatchResourceAttr(latestDataSourceName, "data", regexp.MustCompile(`"schema_version"`)),
+					resource.TestCheckResourceAttrSet(latestDataSourceName, "panels.aws_insights.dashboard.aws_s3_bucket_dashboard"),
+					resource.TestCheckResourceAttr(listDataSourceName, "snapshots.#", "1"),
+					resource.TestCheckResourceAttrPair(listDataSourceName, "snapshots.0.workspace_snapshot_id", resourceName, "workspace_snapshot_id"),
+					resource.TestCheckResourceAttrPair(listDataSourceName, "snapshots.0.share_url", resourceName, "share_url"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserWorkspaceSnapshotDataSourceConfig(workspaceHandle, dataFile string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_snapshot" "snapshot_1" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		data_file        = "%s"
+		tags             = jsonencode({
+			series: "daily_s3"
+		})
+		visibility       = "anyone_with_link"
+	}
+
+	data "steampipecloud_workspace_snapshot" "by_id" {
+		workspace_handle      = steampipecloud_workspace.test_workspace.handle
+		workspace_snapshot_id = steampipecloud_workspace_snapshot.snapshot_1.workspace_snapshot_id
+	}
+
+	data "steampipecloud_workspace_snapshot" "latest" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		dashboard_name   = "aws_insights.dashboard.aws_s3_bucket_dashboard"
+		include_data     = true
+
+		depends_on = [steampipecloud_workspace_snapshot.snapshot_1]
+	}
+
+	data "steampipecloud_workspace_snapshots" "tagged" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		tags             = {
+			series = "daily_s3"
+		}
+
+		depends_on = [steampipecloud_workspace_snapshot.snapshot_1]
+	}`, workspaceHandle, dataFile)
+}
+
+func TestFilterWorkspaceSnapshots(t *testing.T) {
+	snapshot := func(id, dashboard, createdAt string, tags map[string]interface{}) steampipe.WorkspaceSnapshot {
+		return steampipe.WorkspaceSnapshot{Id: id, DashboardName: dashboard, CreatedAt: createdAt, Tags: tags}
+	}
+	snapshots := []steampipe.WorkspaceSnapshot{
+		snapshot("snap_1", "aws_compliance.benchmark.cis_v140", "2022-08-01T10:00:00Z", map[string]interface{}{"series": "daily_cis", "env": "prod"}),
+		snapshot("snap_2", "aws_compliance.benchmark.cis_v140", "2022-08-03T10:00:00Z", map[string]interface{}{"series": "daily_cis"}),
+		snapshot("snap_3", "aws_insights.dashboard.aws_s3_bucket_dashboard", "2022-08-02T10:00:00Z", nil),
+	}
+	tests := []struct {
+		filter   workspaceSnapshotFilter
+		expected []string
+	}{
+		{workspaceSnapshotFilter{}, []string{"snap_2", "snap_3", "snap_1"}},
+		{workspaceSnapshotFilter{DashboardName: "aws_compliance.benchmark.cis_v140"}, []string{"snap_2", "snap_1"}},
+		{workspaceSnapshotFilter{Tags: map[string]string{"series": "daily_cis", "env": "prod"}}, []string{"snap_1"}},
+		{workspaceSnapshotFilter{Tags: map[string]string{"series": "weekly"}}, nil},
+		{workspaceSnapshotFilter{CreatedAfter: time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)}, []string{"snap_2", "snap_3"}},
+		{workspaceSnapshotFilter{CreatedBefore: time.Date(2022, 8, 3, 0, 0, 0, 0, time.UTC)}, []string{"snap_3", "snap_1"}},
+	}
+	for _, test := range tests {
+		var ids []string
+		for _, snapshot := range filterWorkspaceSnapshots(snapshots, test.filter) {
+			ids = append(ids, snapshot.Id)
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("filterWorkspaceSnapshots(%+v) returned %v, expected %v", test.filter, ids, test.expected)
+		}
+	}
+}
+
+func TestWorkspaceSnapshotFilterWhere(t *testing.T) {
+	tests := []struct {
+		filter   workspaceSnapshotFilter
+		expected string
+	}{
+		{workspaceSnapshotFilter{}, ""},
+		{workspaceSnapshotFilter{DashboardName: "aws_compliance.benchmark.cis_v140"}, "dashboard_name = 'aws_compliance.benchmark.cis_v140'"},
+		{
+			workspaceSnapshotFilter{
+				Tags:          map[string]string{"series": "daily_cis", "owner": "o'brien"},
+				CreatedAfter:  time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+			},
+			"tags ->> 'owner' = 'o''brien' and tags ->> 'series' = 'daily_cis' and created_at > '2022-08-01T00:00:00Z' and created_at < '2022-09-01T00:00:00Z'",
+		},
+	}
+	for _, test := range tests {
+		if where := test.filter.where(); where != test.expected {
+			t.Errorf("where() for %+v returned %q, expected %q", test.filter, where, test.expected)
+		}
+	}
+}
+
+func TestFlattenWorkspaceSnapshotData(t *testing.T) {
+	data, err := parseSnapshotData([]byte(testSnapshotData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataJSON, panels := flattenWorkspaceSnapshotData(data)
+	if _, err := parseSnapshotData([]byte(dataJSON)); err != nil {
+		t.Errorf("flattenWorkspaceSnapshotData returned data which does not parse: %v", err)
+	}
+	panel, ok := panels["aws_insights.dashboard.aws_s3_bucket_dashboard"].(string)
+	if !ok || !regexp.MustCompile(`"title":\s*"AWS S3 Bucket Dashboard"`).MatchString(panel) {
+		t.Errorf("flattenWorkspaceSnapshotData returned panels %v", panels)
+	}
+}
@@ -0,0 +1,205 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func dataSourceWorkspaceSnapshots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWorkspaceSnapshotsRead,
+		Schema: map[string]*schema.Schema{
+			"organization": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: false,
+			},
+			"workspace_handle": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z0-9]{1,23}$`), "Handle must be between 1 and 23 characters, and may only contain alphanumeric characters."),
+			},
+			"dashboard_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"created_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"created_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"include_data": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"snapshots": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Resource{Schema: workspaceSnapshotDataSourceSchema()},
+			},
+		},
+	}
+}
+
+// workspaceSnapshotFilter holds the conditions a snapshot must meet to be returned by the snapshot data sources.
+// Empty conditions match every snapshot.
+type workspaceSnapshotFilter struct {
+	DashboardName string
+	Tags          map[string]string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+func (f workspaceSnapshotFilter) matches(snapshot steampipe.WorkspaceSnapshot) bool {
+	if f.DashboardName != "" && snapshot.DashboardName != f.DashboardName {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tags, _ := snapshot.Tags.(map[string]interface{})
+		for key, value := range f.Tags {
+			if tag, ok := tags[key]; !ok || fmt.Sprintf("%v", tag) != value {
+				return false
+			}
+		}
+	}
+	if !f.CreatedAfter.IsZero() || !f.CreatedBefore.IsZero() {
+		createdAt, err := time.Parse(time.RFC3339, snapshot.CreatedAt)
+		if err != nil {
+			return false
+		}
+		if !f.CreatedAfter.IsZero() && !createdAt.After(f.CreatedAfter) {
+			return false
+		}
+		if !f.CreatedBefore.IsZero() && !createdAt.Before(f.CreatedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// where returns the where clause which filters the snapshots when they are listed, so that only the matching
+// snapshots are paged through. The snapshots are still matched against the filter once they are listed.
+func (f workspaceSnapshotFilter) where() string {
+	var conditions []string
+	if f.DashboardName != "" {
+		conditions = append(conditions, fmt.Sprintf("dashboard_name = %s", sqlQuote(f.DashboardName)))
+	}
+	for _, key := range sortedKeys(f.Tags) {
+		conditions = append(conditions, fmt.Sprintf("tags ->> %s = %s", sqlQuote(key), sqlQuote(f.Tags[key])))
+	}
+	if !f.CreatedAfter.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at > %s", sqlQuote(f.CreatedAfter.Format(time.RFC3339))))
+	}
+	if !f.CreatedBefore.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at < %s", sqlQuote(f.CreatedBefore.Format(time.RFC3339))))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// filterWorkspaceSnapshots returns the snapshots which match the filter, most recent first
+func filterWorkspaceSnapshots(snapshots []steampipe.WorkspaceSnapshot, filter workspaceSnapshotFilter) []steampipe.WorkspaceSnapshot {
+	var matched []steampipe.WorkspaceSnapshot
+	for _, snapshot := range snapshots {
+		if filter.matches(snapshot) {
+			matched = append(matched, snapshot)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].CreatedAt > matched[j].CreatedAt
+	})
+	return matched
+}
+
+func dataSourceWorkspaceSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	var diags diag.Diagnostics
+	var userHandle string
+	var r *http.Response
+	var err error
+
+	workspaceHandle := d.Get("workspace_handle").(string)
+	filter := workspaceSnapshotFilter{
+		DashboardName: d.Get("dashboard_name").(string),
+		Tags:          map[string]string{},
+	}
+	for key, value := range d.Get("tags").(map[string]interface{}) {
+		filter.Tags[key] = value.(string)
+	}
+	if createdAfter := d.Get("created_after").(string); createdAfter != "" {
+		filter.CreatedAfter, err = time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return diag.Errorf("error parsing created_after %s: %v", createdAfter, err)
+		}
+	}
+	if createdBefore := d.Get("created_before").(string); createdBefore != "" {
+		filter.CreatedBefore, err = time.Parse(time.RFC3339, createdBefore)
+		if err != nil {
+			return diag.Errorf("error parsing created_before %s: %v", createdBefore, err)
+		}
+	}
+
+	isUser, orgHandle := isUserConnection(d)
+	if isUser {
+		userHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return diag.Errorf("dataSourceWorkspaceSnapshotsRead.getUserHandler error  %v", decodeResponse(r))
+		}
+	}
+
+	snapshots, r, err := listWorkspaceSnapshots(ctx, client, orgHandle, workspaceHandle, filter.where())
+	if err != nil {
+		return diag.Errorf("error listing snapshots of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+	snapshots = filterWorkspaceSnapshots(snapshots, filter)
+	log.Printf("\n[DEBUG] Snapshots Received: %d for Workspace: %s", len(snapshots), workspaceHandle)
+
+	var items []map[string]interface{}
+	for _, snapshot := range snapshots {
+		item := flattenWorkspaceSnapshot(consoleURL(client), userHandle, orgHandle, workspaceHandle, snapshot)
+		if d.Get("include_data").(bool) {
+			data, r, err := downloadWorkspaceSnapshot(ctx, client, userHandle, orgHandle, workspaceHandle, snapshot.Id)
+			if err != nil {
+				return diag.Errorf("error downloading workspace snapshot %s: %v", snapshot.Id, decodeResponse(r))
+			}
+			item["data"], item["panels"] = flattenWorkspaceSnapshotData(data)
+		}
+		items = append(items, item)
+	}
+	if err := d.Set("snapshots", items); err != nil {
+		return diag.Errorf("error setting snapshots: %v", err)
+	}
+	d.Set("organization", orgHandle)
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	if orgHandle != "" {
+		d.SetId(fmt.Sprintf("%s/%s", orgHandle, workspaceHandle))
+	} else {
+		d.SetId(workspaceHandle)
+	}
+
+	return diags
+}
@@ -0,0 +1,148 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// The port on which workspace databases accept Postgres connections
+const workspaceDatabasePort = 9193
+
+type ephemeralWorkspaceCredentials struct {
+	client *SteampipeClient
+}
+
+type ephemeralWorkspaceCredentialsModel struct {
+	Organization      types.String `tfsdk:"organization"`
+	WorkspaceHandle   types.String `tfsdk:"workspace_handle"`
+	Host              types.String `tfsdk:"host"`
+	Port              types.Int64  `tfsdk:"port"`
+	DatabaseName      types.String `tfsdk:"database_name"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PasswordExpiresAt types.String `tfsdk:"password_expires_at"`
+	ConnectionString  types.String `tfsdk:"connection_string"`
+}
+
+// NewEphemeralWorkspaceCredentials
+func NewEphemeralWorkspaceCredentials() ephemeral.EphemeralResource {
+	return &ephemeralWorkspaceCredentials{}
+}
+
+func (e *ephemeralWorkspaceCredentials) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_credentials"
+}
+
+func (e *ephemeralWorkspaceCredentials) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"organization": schema.StringAttribute{
+				Optional: true,
+			},
+			"workspace_handle": schema.StringAttribute{
+				Required: true,
+			},
+			"host": schema.StringAttribute{
+				Computed: true,
+			},
+			"port": schema.Int64Attribute{
+				Computed: true,
+			},
+			"database_name": schema.StringAttribute{
+				Computed: true,
+			},
+			"username": schema.StringAttribute{
+				Computed: true,
+			},
+			"password": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"password_expires_at": schema.StringAttribute{
+				Computed: true,
+			},
+			"connection_string": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func (e *ephemeralWorkspaceCredentials) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*SteampipeClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *SteampipeClient, got: %T", req.ProviderData))
+		return
+	}
+	e.client = client
+}
+
+func (e *ephemeralWorkspaceCredentials) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralWorkspaceCredentialsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var workspace steampipe.Workspace
+	var actorHandle string
+	var r *http.Response
+	var err error
+
+	workspaceHandle := data.WorkspaceHandle.ValueString()
+	orgHandle := data.Organization.ValueString()
+
+	// The database credentials always belong to the calling user, even for organization workspaces
+	actorHandle, r, err = getUserHandler(ctx, e.client)
+	if err != nil {
+		resp.Diagnostics.AddError("ephemeralWorkspaceCredentialsOpen.getUserHandler error", actionErrorDetail(r, err))
+		return
+	}
+
+	if orgHandle == "" {
+		workspace, r, err = e.client.APIClient.UserWorkspaces.Get(ctx, actorHandle, workspaceHandle).Execute()
+	} else {
+		workspace, r, err = e.client.APIClient.OrgWorkspaces.Get(ctx, orgHandle, workspaceHandle).Execute()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error reading workspace %s", workspaceHandle), actionErrorDetail(r, err))
+		return
+	}
+
+	password, r, err := e.client.APIClient.Users.GetDBPassword(ctx, actorHandle).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError("error reading database password", actionErrorDetail(r, err))
+		return
+	}
+
+	host := types.StringPointerValue(workspace.Host).ValueString()
+	databaseName := types.StringPointerValue(workspace.DatabaseName).ValueString()
+	connectionString := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(actorHandle, password.Password),
+		Host:     fmt.Sprintf("%s:%d", host, workspaceDatabasePort),
+		Path:     databaseName,
+		RawQuery: "sslmode=require",
+	}
+
+	data.Host = types.StringValue(host)
+	data.Port = types.Int64Value(workspaceDatabasePort)
+	data.DatabaseName = types.StringValue(databaseName)
+	data.Username = types.StringValue(actorHandle)
+	data.Password = types.StringValue(password.Password)
+	data.PasswordExpiresAt = types.StringPointerValue(password.ExpiresAt)
+	data.ConnectionString = types.StringValue(connectionString.String())
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
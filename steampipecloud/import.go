@@ -0,0 +1,189 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// Immutable IDs of organizations, workspaces and connections, e.g. c_cbmbq8n0id9j6t7ldfcg
+var importIDRegex = regexp.MustCompile(`^[cow]_[a-z0-9]{20}$`)
+
+// importedItem is an organization, workspace or connection found by its immutable ID
+type importedItem struct {
+	Handle string
+	// OwnerOrgHandle is the handle of the organization that owns a workspace or connection, empty if a user owns it
+	OwnerOrgHandle string
+}
+
+type importIDLookup func(id string) (*importedItem, error)
+
+// importStatePassthroughByID is a passthrough importer which also accepts the immutable IDs of organizations (o_...),
+// workspaces (w_...) and connections (c_...) in place of their handles, and resolves them to the canonical handle
+// based ID, e.g. "c_cbmbq8n0id9j6t7ldfcg" to "myorg/aws" or "w_cbmbq8n0id9j6t7ldfcg/aws" to "myorg/dev/aws"
+func importStatePassthroughByID(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*SteampipeClient)
+	id, err := resolveImportID(d.Id(), func(id string) (*importedItem, error) {
+		return lookupImportID(ctx, client, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(id)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveImportID replaces each part of the import ID that is an immutable ID with its handle. If the first part is
+// a workspace or connection owned by an organization, the organization handle is prepended.
+func resolveImportID(importID string, lookup importIDLookup) (string, error) {
+	// IDs using the legacy ":" separator are passed through as they are
+	if strings.Contains(importID, ":") {
+		return importID, nil
+	}
+
+	idParts := strings.Split(importID, "/")
+	var resolved []string
+	for i, part := range idParts {
+		if !importIDRegex.MatchString(part) {
+			resolved = append(resolved, part)
+			continue
+		}
+		item, err := lookup(part)
+		if err != nil {
+			return "", err
+		}
+		// handles can look like IDs, so anything that can't be found is treated as a handle
+		if item == nil {
+			resolved = append(resolved, part)
+			continue
+		}
+		if i == 0 && item.OwnerOrgHandle != "" {
+			resolved = append(resolved, item.OwnerOrgHandle)
+		}
+		resolved = append(resolved, item.Handle)
+	}
+	return strings.Join(resolved, "/"), nil
+}
+
+func lookupImportID(ctx context.Context, client *SteampipeClient, id string) (*importedItem, error) {
+	switch {
+	case strings.HasPrefix(id, "o_"):
+		orgHandle, err := getOrgHandleByID(ctx, client, id)
+		if err != nil || orgHandle == "" {
+			return nil, err
+		}
+		return &importedItem{Handle: orgHandle}, nil
+	case strings.HasPrefix(id, "w_"):
+		return lookupWorkspaceByID(ctx, client, id)
+	case strings.HasPrefix(id, "c_"):
+		return lookupConnectionByID(ctx, client, id)
+	}
+	return nil, nil
+}
+
+// getOrgHandleByID returns the handle of an organization the user is a member of, or an empty string if there is none
+func getOrgHandleByID(ctx context.Context, client *SteampipeClient, orgID string) (string, error) {
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft {
+		req := client.APIClient.Actors.ListOrgs(ctx)
+		if nextToken != "" {
+			req = req.NextToken(nextToken)
+		}
+		resp, r, err := req.Execute()
+		if err != nil {
+			return "", fmt.Errorf("error listing organizations: %v", decodeResponse(r))
+		}
+		for _, userOrg := range resp.GetItems() {
+			if userOrg.OrgId == orgID && userOrg.Org != nil {
+				return userOrg.Org.Handle, nil
+			}
+		}
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	return "", nil
+}
+
+// getOwnerOrgHandle returns the handle of the organization for an identity ID, or an empty string for a user
+func getOwnerOrgHandle(ctx context.Context, client *SteampipeClient, identityID string) (string, error) {
+	if !strings.HasPrefix(identityID, "o_") {
+		return "", nil
+	}
+	orgHandle, err := getOrgHandleByID(ctx, client, identityID)
+	if err != nil {
+		return "", err
+	}
+	if orgHandle == "" {
+		return "", fmt.Errorf("organization %s not found", identityID)
+	}
+	return orgHandle, nil
+}
+
+func lookupWorkspaceByID(ctx context.Context, client *SteampipeClient, workspaceID string) (*importedItem, error) {
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft {
+		req := client.APIClient.Actors.ListWorkspaces(ctx)
+		if nextToken != "" {
+			req = req.NextToken(nextToken)
+		}
+		resp, r, err := req.Execute()
+		if err != nil {
+			return nil, fmt.Errorf("error listing workspaces: %v", decodeResponse(r))
+		}
+		for _, workspace := range resp.GetItems() {
+			if workspace.GetWorkspaceId() != workspaceID && workspace.GetId() != workspaceID {
+				continue
+			}
+			item := &importedItem{Handle: workspace.GetHandle()}
+			if identity := workspace.Identity; identity != nil && identity.Type == "org" {
+				item.OwnerOrgHandle = identity.Handle
+			} else if item.OwnerOrgHandle, err = getOwnerOrgHandle(ctx, client, workspace.GetIdentityId()); err != nil {
+				return nil, err
+			}
+			return item, nil
+		}
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	return nil, nil
+}
+
+func lookupConnectionByID(ctx context.Context, client *SteampipeClient, connectionID string) (*importedItem, error) {
+	var connection *steampipe.Connection
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft && connection == nil {
+		req := client.APIClient.Actors.ListConnections(ctx)
+		if nextToken != "" {
+			req = req.NextToken(nextToken)
+		}
+		resp, r, err := req.Execute()
+		if err != nil {
+			return nil, fmt.Errorf("error listing connections: %v", decodeResponse(r))
+		}
+		for _, item := range resp.GetItems() {
+			if item.Id == connectionID {
+				item := item
+				connection = &item
+				break
+			}
+		}
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	if connection == nil {
+		return nil, nil
+	}
+
+	orgHandle, err := getOwnerOrgHandle(ctx, client, connection.IdentityId)
+	if err != nil {
+		return nil, err
+	}
+	return &importedItem{Handle: connection.Handle, OwnerOrgHandle: orgHandle}, nil
+}
@@ -0,0 +1,37 @@
+package steampipecloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveImportID(t *testing.T) {
+	items := map[string]*importedItem{
+		"o_aaaaaaaaaaaaaaaaaaaa": {Handle: "myorg"},
+		"w_aaaaaaaaaaaaaaaaaaaa": {Handle: "dev", OwnerOrgHandle: "myorg"},
+		"w_bbbbbbbbbbbbbbbbbbbb": {Handle: "personal"},
+		"c_aaaaaaaaaaaaaaaaaaaa": {Handle: "aws", OwnerOrgHandle: "myorg"},
+	}
+	lookup := func(id string) (*importedItem, error) {
+		return items[id], nil
+	}
+
+	for importID, expected := range map[string]string{
+		"aws":                    "aws",
+		"myorg/aws":              "myorg/aws",
+		"myorg:aws":              "myorg:aws",
+		"o_aaaaaaaaaaaaaaaaaaaa": "myorg",
+		"c_aaaaaaaaaaaaaaaaaaaa": "myorg/aws",
+		"w_aaaaaaaaaaaaaaaaaaaa": "myorg/dev",
+		"w_bbbbbbbbbbbbbbbbbbbb": "personal",
+		"w_aaaaaaaaaaaaaaaaaaaa/c_aaaaaaaaaaaaaaaaaaaa": "myorg/dev/aws",
+		"o_aaaaaaaaaaaaaaaaaaaa/dev/aws":                "myorg/dev/aws",
+		// handles which look like IDs are kept when there is no match
+		"c_cccccccccccccccccccc": "c_cccccccccccccccccccc",
+	} {
+		id, err := resolveImportID(importID, lookup)
+		require.NoError(t, err)
+		require.Equal(t, expected, id, importID)
+	}
+}
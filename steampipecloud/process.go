@@ -0,0 +1,180 @@
+package steampipecloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// The default time to wait for a process to finish
+const defaultProcessTimeout = 20 * time.Minute
+
+const (
+	processStateCompleted = "completed"
+	processStateFailed    = "failed"
+	processStateCancelled = "cancelled"
+	// The US spelling of cancelled, which is also accepted as the SDK does not define the process states
+	processStateCanceled = "canceled"
+)
+
+// isProcessTerminal returns whether a process has stopped running, successfully or not
+func isProcessTerminal(state string) bool {
+	switch state {
+	case processStateCompleted, processStateFailed, processStateCancelled, processStateCanceled:
+		return true
+	}
+	return false
+}
+
+// getProcess gets an identity process, or a workspace process if workspaceHandle is set. The process
+// belongs to the organization if orgHandle is set, otherwise to the user with userHandle.
+func getProcess(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, processId string) (steampipe.SpProcess, *http.Response, error) {
+	if orgHandle == "" {
+		if workspaceHandle == "" {
+			return client.APIClient.UserProcesses.Get(ctx, userHandle, processId).Execute()
+		}
+		return client.APIClient.UserWorkspaceProcesses.Get(ctx, userHandle, workspaceHandle, processId).Execute()
+	}
+	if workspaceHandle == "" {
+		return client.APIClient.OrgProcesses.Get(ctx, orgHandle, processId).Execute()
+	}
+	return client.APIClient.OrgWorkspaceProcesses.Get(ctx, orgHandle, workspaceHandle, processId).Execute()
+}
+
+// waitForProcess polls a process until it reaches a terminal state or the timeout expires
+func waitForProcess(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, processId string, timeout time.Duration) (steampipe.SpProcess, error) {
+	var process steampipe.SpProcess
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		var r *http.Response
+		var err error
+		process, r, err = getProcess(ctx, client, userHandle, orgHandle, workspaceHandle, processId)
+		if err != nil {
+			if r == nil {
+				return resource.NonRetryableError(fmt.Errorf("error getting process %s: %v", processId, err))
+			}
+			return resource.NonRetryableError(fmt.Errorf("error getting process %s: %v", processId, decodeResponse(r)))
+		}
+		state := types.SafeString(process.State)
+		log.Printf("\n[DEBUG] Process: %s is %s", processId, state)
+		if !isProcessTerminal(state) {
+			return resource.RetryableError(fmt.Errorf("process %s is %s", processId, state))
+		}
+		return nil
+	})
+	return process, err
+}
+
+// listProcesses returns all of the identity processes, or the workspace processes if workspaceHandle is set. The
+// processes belong to the organization if orgHandle is set, otherwise to the user with userHandle. Workspace
+// processes are filtered with the where clause, which identity process lists do not support.
+func listProcesses(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, where string) ([]steampipe.SpProcess, *http.Response, error) {
+	var items []steampipe.SpProcess
+	var r *http.Response
+	var err error
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft {
+		var resp steampipe.ListProcessesResponse
+		switch {
+		case orgHandle == "" && workspaceHandle == "":
+			req := client.APIClient.UserProcesses.List(ctx, userHandle)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		case orgHandle == "":
+			req := client.APIClient.UserWorkspaceProcesses.List(ctx, userHandle, workspaceHandle)
+			if where != "" {
+				req = req.Where(where)
+			}
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		case workspaceHandle == "":
+			req := client.APIClient.OrgProcesses.List(ctx, orgHandle)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		default:
+			req := client.APIClient.OrgWorkspaceProcesses.List(ctx, orgHandle, workspaceHandle)
+			if where != "" {
+				req = req.Where(where)
+			}
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		}
+		if err != nil {
+			return nil, r, err
+		}
+		items = append(items, resp.GetItems()...)
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	return items, r, nil
+}
+
+// getProcessLog gets the log of an identity process, or a workspace process if workspaceHandle is set, as JSON lines
+func getProcessLog(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, processId string) (string, *http.Response, error) {
+	if orgHandle == "" {
+		if workspaceHandle == "" {
+			return client.APIClient.UserProcesses.Log(ctx, userHandle, processId, "process", "jsonl").Execute()
+		}
+		return client.APIClient.UserWorkspaceProcesses.Log(ctx, userHandle, workspaceHandle, processId, "process", "jsonl").Execute()
+	}
+	if workspaceHandle == "" {
+		return client.APIClient.OrgProcesses.Log(ctx, orgHandle, processId, "process", "jsonl").Execute()
+	}
+	return client.APIClient.OrgWorkspaceProcesses.Log(ctx, orgHandle, workspaceHandle, processId, "process", "jsonl").Execute()
+}
+
+// Snapshot IDs, e.g. snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl
+var snapshotIDRegex = regexp.MustCompile(`snap_[0-9a-v]{20}_[0-9a-z]+`)
+
+// parseProcessLog returns the message of the last error logged by a process, and the ID of the last snapshot
+// referenced in the log, if any
+func parseProcessLog(processLog string) (errorMessage, snapshotId string) {
+	for _, line := range strings.Split(processLog, "\n") {
+		if match := snapshotIDRegex.FindString(line); match != "" {
+			snapshotId = match
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		if level, _ := entry["level"].(string); !strings.EqualFold(level, "error") {
+			continue
+		}
+		for _, key := range []string{"message", "msg", "error"} {
+			if message, ok := entry[key].(string); ok && message != "" {
+				errorMessage = message
+				break
+			}
+		}
+	}
+	return errorMessage, snapshotId
+}
+
+// parseProcessTimeout parses how long to wait for a process, e.g. "30m", defaulting to defaultProcessTimeout
+func parseProcessTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return defaultProcessTimeout, nil
+	}
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("timeout %q must be a positive duration, e.g. 30m", timeout)
+	}
+	return duration, nil
+}
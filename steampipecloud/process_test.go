@@ -0,0 +1,121 @@
+package steampipecloud
+
+import (
+	"testing"
+	"time"
+
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func TestParseProcessLog(t *testing.T) {
+	processLog := `{"level":"info","message":"starting pipeline.snapshot_dashboard"}
+{"level":"info","message":"uploaded snapshot snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl"}
+{"level":"error","message":"failed to send notification"}
+not json
+{"level":"ERROR","msg":"notification target not found"}`
+
+	errorMessage, snapshotId := parseProcessLog(processLog)
+	if errorMessage != "notification target not found" {
+		t.Errorf("parseProcessLog returned error message %q", errorMessage)
+	}
+	if snapshotId != "snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl" {
+		t.Errorf("parseProcessLog returned snapshot ID %q", snapshotId)
+	}
+
+	errorMessage, snapshotId = parseProcessLog(`{"level":"info","message":"done"}`)
+	if errorMessage != "" || snapshotId != "" {
+		t.Errorf("parseProcessLog returned %q, %q for a log without errors or snapshots", errorMessage, snapshotId)
+	}
+}
+
+func TestIsProcessTerminal(t *testing.T) {
+	tests := map[string]bool{
+		processStateCompleted: true,
+		processStateFailed:    true,
+		"cancelled":           true,
+		"canceled":            true,
+		"pending":             false,
+		"running":             false,
+		"":                    false,
+	}
+	for state, expected := range tests {
+		if terminal := isProcessTerminal(state); terminal != expected {
+			t.Errorf("isProcessTerminal(%q) returned %t, expected %t", state, terminal, expected)
+		}
+	}
+}
+
+func TestProcessDuration(t *testing.T) {
+	completed, running, cancelled := processStateCompleted, "running", processStateCancelled
+	tests := []struct {
+		process  steampipe.SpProcess
+		expected string
+	}{
+		{steampipe.SpProcess{State: &completed, CreatedAt: "2022-08-01T10:00:00Z", UpdatedAt: "2022-08-01T10:01:30Z"}, "1m30s"},
+		{steampipe.SpProcess{State: &running, CreatedAt: "2022-08-01T10:00:00Z", UpdatedAt: "2022-08-01T10:01:30Z"}, ""},
+		{steampipe.SpProcess{State: &cancelled, CreatedAt: "2022-08-01T10:00:00Z", UpdatedAt: "2022-08-01T10:00:45Z"}, "45s"},
+		{steampipe.SpProcess{State: &completed, CreatedAt: "yesterday", UpdatedAt: "2022-08-01T10:01:30Z"}, ""},
+	}
+	for _, test := range tests {
+		if duration := processDuration(test.process); duration != test.expected {
+			t.Errorf("processDuration(%v) returned %q, expected %q", test.process, duration, test.expected)
+		}
+	}
+}
+
+func TestFilterProcesses(t *testing.T) {
+	completed, failed := processStateCompleted, processStateFailed
+	pipelineId := "p_cbqgah8smpv7n7sg9o0g"
+	processes := []steampipe.SpProcess{
+		{Id: "p_1", Type: "pipeline.command.run", State: &completed, PipelineId: &pipelineId, CreatedAt: "2022-08-01T10:00:00Z"},
+		{Id: "p_2", Type: "pipeline.command.run", State: &failed, PipelineId: &pipelineId, CreatedAt: "2022-08-02T10:00:00Z"},
+		{Id: "p_3", Type: "workspace.command.reboot", State: &failed, CreatedAt: "2022-08-03T10:00:00Z"},
+		{Id: "p_4", Type: "workspace.command.reboot", CreatedAt: "2022-08-04T10:00:00Z"},
+	}
+	tests := []struct {
+		filter   processFilter
+		expected []string
+	}{
+		{processFilter{}, []string{"p_1", "p_2", "p_3", "p_4"}},
+		{processFilter{Type: "pipeline.command.run"}, []string{"p_1", "p_2"}},
+		{processFilter{State: processStateFailed}, []string{"p_2", "p_3"}},
+		{processFilter{PipelineId: pipelineId, State: processStateFailed}, []string{"p_2"}},
+		{processFilter{CreatedAfter: time.Date(2022, 8, 2, 10, 0, 0, 0, time.UTC)}, []string{"p_3", "p_4"}},
+		{processFilter{Type: "pipeline.command.run", CreatedAfter: time.Date(2022, 8, 3, 0, 0, 0, 0, time.UTC)}, nil},
+	}
+	for _, test := range tests {
+		var ids []string
+		for _, process := range filterProcesses(processes, test.filter) {
+			ids = append(ids, process.Id)
+		}
+		if len(ids) != len(test.expected) {
+			t.Errorf("filterProcesses(%+v) returned %v, expected %v", test.filter, ids, test.expected)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != test.expected[i] {
+				t.Errorf("filterProcesses(%+v) returned %v, expected %v", test.filter, ids, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestProcessFilterWhere(t *testing.T) {
+	tests := []struct {
+		filter   processFilter
+		expected string
+	}{
+		{processFilter{}, ""},
+		{processFilter{Type: "pipeline.command.run"}, "type = 'pipeline.command.run'"},
+		{
+			processFilter{State: processStateFailed, PipelineId: "p_cbqgah8smpv7n7sg9o0g", CreatedAfter: time.Date(2022, 8, 2, 10, 0, 0, 0, time.UTC)},
+			"state = 'failed' and pipeline_id = 'p_cbqgah8smpv7n7sg9o0g' and created_at > '2022-08-02T10:00:00Z'",
+		},
+	}
+	for _, test := range tests {
+		if where := test.filter.where(); where != test.expected {
+			t.Errorf("where() for %+v returned %q, expected %q", test.filter, where, test.expected)
+		}
+	}
+}
@@ -39,15 +39,21 @@ func Provider() *schema.Provider {
 			"steampipecloud_workspace":                     resourceWorkspace(),
 			"steampipecloud_workspace_aggregator":          resourceWorkspaceAggregator(),
 			"steampipecloud_workspace_connection":          resourceWorkspaceConnection(),
+			"steampipecloud_workspace_connections":         resourceWorkspaceConnections(),
 			"steampipecloud_workspace_mod":                 resourceWorkspaceMod(),
 			"steampipecloud_workspace_mod_variable":        resourceWorkspaceModVariable(),
 			"steampipecloud_workspace_pipeline":            resourceWorkspacePipeline(),
+			"steampipecloud_workspace_plugin_aggregators":  resourceWorkspacePluginAggregators(),
 			"steampipecloud_workspace_snapshot":            resourceWorkspaceSnapshot(),
+			"steampipecloud_workspace_snapshot_retention":  resourceWorkspaceSnapshotRetention(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"steampipecloud_organization": dataSourceOrganization(),
-			"steampipecloud_process":      dataSourceProcess(),
-			"steampipecloud_user":         dataSourceUser(),
+			"steampipecloud_organization":        dataSourceOrganization(),
+			"steampipecloud_process":             dataSourceProcess(),
+			"steampipecloud_processes":           dataSourceProcesses(),
+			"steampipecloud_user":                dataSourceUser(),
+			"steampipecloud_workspace_snapshot":  dataSourceWorkspaceSnapshot(),
+			"steampipecloud_workspace_snapshots": dataSourceWorkspaceSnapshots(),
 		},
 
 		ConfigureContextFunc: providerConfigure,
@@ -66,6 +72,10 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
+	if connectionPluginCatalogErr != nil {
+		return nil, diag.FromErr(connectionPluginCatalogErr)
+	}
+
 	apiClient, err := CreateClient(&config, diags)
 	if err != nil {
 		return nil, err
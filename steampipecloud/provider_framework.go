@@ -0,0 +1,117 @@
+package steampipecloud
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// ProviderServerFactory combines the SDKv2 provider with the plugin framework provider, which serves the
+// features that are only available through the framework, such as ephemeral resources and actions.
+func ProviderServerFactory(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	providers := []func() tfprotov5.ProviderServer{
+		Provider().GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
+
+type frameworkProvider struct{}
+
+type frameworkProviderModel struct {
+	Token types.String `tfsdk:"token"`
+	Host  types.String `tfsdk:"host"`
+}
+
+// NewFrameworkProvider
+func NewFrameworkProvider() provider.Provider {
+	return &frameworkProvider{}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "steampipecloud"
+}
+
+// Schema must match the SDKv2 provider schema exactly, since both providers are served through the same mux server.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema := Provider().Schema
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"token": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["token"].Description,
+			},
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["host"].Description,
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := Config{
+		Token: data.Token.ValueString(),
+		Host:  data.Host.ValueString(),
+	}
+	if config.Host == "" {
+		config.Host = os.Getenv("STEAMPIPE_CLOUD_HOST")
+	}
+
+	apiClient, diags := CreateClient(&config, nil)
+	for _, d := range diags {
+		resp.Diagnostics.AddError(d.Summary, d.Detail)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := &SteampipeClient{
+		APIClient: apiClient,
+		Config:    &config,
+	}
+	resp.EphemeralResourceData = client
+	resp.ActionData = client
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewActionPipelineRun,
+		NewActionWorkspaceModUpdate,
+	}
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewEphemeralWorkspaceCredentials,
+	}
+}
@@ -1,9 +1,13 @@
 package steampipecloud
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -32,3 +36,53 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("STEAMPIPE_CLOUD_TOKEN must be set for acceptance tests")
 	}
 }
+
+func TestProviderServerFactory(t *testing.T) {
+	ctx := context.Background()
+	serverFactory, err := ProviderServerFactory(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := serverFactory().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("err: %s: %s", d.Summary, d.Detail)
+		}
+	}
+	if _, ok := resp.EphemeralResourceSchemas["steampipecloud_workspace_credentials"]; !ok {
+		t.Fatal("ephemeral resource steampipecloud_workspace_credentials is not served")
+	}
+	for _, name := range []string{"steampipecloud_pipeline_run", "steampipecloud_workspace_mod_update"} {
+		if _, ok := resp.ActionSchemas[name]; !ok {
+			t.Fatalf("action %s is not served", name)
+		}
+	}
+}
+
+func TestParseActionTimeout(t *testing.T) {
+	tests := []struct {
+		timeout  types.String
+		expected time.Duration
+		valid    bool
+	}{
+		{types.StringNull(), defaultProcessTimeout, true},
+		{types.StringValue(""), defaultProcessTimeout, true},
+		{types.StringValue("45s"), 45 * time.Second, true},
+		{types.StringValue("1h"), time.Hour, true},
+		{types.StringValue("0s"), 0, false},
+		{types.StringValue("soon"), 0, false},
+	}
+	for _, test := range tests {
+		timeout, err := parseActionTimeout(test.timeout)
+		if test.valid && (err != nil || timeout != test.expected) {
+			t.Errorf("parseActionTimeout(%s) returned %v, %v, expected %v", test.timeout, timeout, err, test.expected)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("parseActionTimeout(%s) expected an error", test.timeout)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// newFakeServerClient returns a client for a fake Steampipe Cloud API, which returns the actor testuser and
+// responds to every other request with the given status
+func newFakeServerClient(t *testing.T, status int) *SteampipeClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v0/actor" {
+			w.Write([]byte(`{"id": "u_c7rtpfcconkqh8as4e2g", "handle": "testuser"}`))
+			return
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(fmt.Sprintf(`{"status": %d, "title": %q, "detail": "fake server"}`, status, http.StatusText(status))))
+	}))
+	t.Cleanup(server.Close)
+
+	configuration := steampipe.NewConfiguration()
+	configuration.Servers = []steampipe.ServerConfiguration{{URL: server.URL + "/api/v0"}}
+	configuration.HTTPClient = server.Client()
+	return &SteampipeClient{APIClient: steampipe.NewAPIClient(configuration), Config: &Config{}}
+}
+
+// testResourceDataFromState returns the resource data for a state with the given attributes, as Terraform passes it
+// to Read. Attributes which are not given are null, as they are after an import.
+func testResourceDataFromState(t *testing.T, r *schema.Resource, attributes map[string]cty.Value) *schema.ResourceData {
+	values := map[string]cty.Value{}
+	for name, attributeType := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		values[name] = cty.NullVal(attributeType)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	stateValue := cty.ObjectVal(values)
+	state, err := r.ShimInstanceStateFromValue(stateValue)
+	require.NoError(t, err)
+	state.RawState = stateValue
+	return r.Data(state)
+}
+
+var notFoundResources = []struct {
+	name     string
+	resource *schema.Resource
+	ids      []string
+}{
+	{"steampipecloud_workspace_snapshot", resourceWorkspaceSnapshot(), []string{"dev/snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl", "testorg/dev/snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl"}},
+	{"steampipecloud_workspace_pipeline", resourceWorkspacePipeline(), []string{"dev/p_cbqgah8smpv7n7sg9o0g", "testorg/dev/p_cbqgah8smpv7n7sg9o0g"}},
+	{"steampipecloud_workspace_aggregator", resourceWorkspaceAggregator(), []string{"dev/all_aws", "testorg/dev/all_aws"}},
+	{"steampipecloud_workspace_mod", resourceWorkspaceMod(), []string{"dev/aws_compliance", "testorg/dev/aws_compliance"}},
+	{"steampipecloud_workspace_mod_variable", resourceWorkspaceModVariable(), []string{"dev/aws_compliance/regions", "testorg/dev/aws_compliance/regions"}},
+	{"steampipecloud_workspace_connection", resourceWorkspaceConnection(), []string{"dev/aws", "testorg/dev/aws"}},
+	{"steampipecloud_organization_workspace_member", resourceOrganizationWorkspaceMember(), []string{"testorg/dev/testuser"}},
+}
+
+func TestReadNotFound(t *testing.T) {
+	client := newFakeServerClient(t, http.StatusNotFound)
+	for _, test := range notFoundResources {
+		for _, id := range test.ids {
+			d := test.resource.TestResourceData()
+			d.SetId(id)
+			diags := test.resource.ReadContext(context.Background(), d, client)
+			if diags.HasError() {
+				t.Errorf("%s read of %s returned errors: %v", test.name, id, diags)
+				continue
+			}
+			if len(diags) != 1 || diags[0].Severity != diag.Warning {
+				t.Errorf("%s read of %s returned %v, expected a warning", test.name, id, diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("%s read of %s did not remove the resource from the state", test.name, id)
+			}
+		}
+	}
+}
+
+func TestReadServerError(t *testing.T) {
+	client := newFakeServerClient(t, http.StatusInternalServerError)
+	for _, test := range notFoundResources {
+		for _, id := range test.ids {
+			d := test.resource.TestResourceData()
+			d.SetId(id)
+			diags := test.resource.ReadContext(context.Background(), d, client)
+			if !diags.HasError() {
+				t.Errorf("%s read of %s returned no error for a server error", test.name, id)
+			}
+			if d.Id() != id {
+				t.Errorf("%s read of %s removed the resource from the state for a server error", test.name, id)
+			}
+		}
+	}
+}
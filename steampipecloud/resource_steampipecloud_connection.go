@@ -7,23 +7,34 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/turbot/go-kit/types"
 	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
+// The verification status recorded when the connection test request itself fails
+const connectionVerificationFailed = "failed"
+
 func resourceConnection() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext: resourceConnectionCreate,
 		ReadContext:   resourceConnectionRead,
 		UpdateContext: resourceConnectionUpdate,
 		DeleteContext: resourceConnectionDelete,
+		CustomizeDiff: customdiff.Sequence(forceNewOnOrganizationChange, resourceConnectionCustomizeDiff),
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateConnectionConfigRaw,
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughByID,
 		},
 		Schema: map[string]*schema.Schema{
 			"connection_id": {
@@ -36,6 +47,7 @@ func resourceConnection() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 			},
 			"handle": {
 				Type:         schema.TypeString,
@@ -47,9 +59,23 @@ func resourceConnection() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			// Steampipe Cloud installs the plugin version, and ignores a version sent with the connection
 			"plugin_version": {
 				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"plugin_version_constraint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVersionConstraint,
+			},
+			"verify": {
+				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  false,
+			},
+			"verification_status": {
+				Type:     schema.TypeString,
 				Computed: true,
 			},
 			"type": {
@@ -89,16 +115,45 @@ func resourceConnection() *schema.Resource {
 				Optional:         true,
 				ValidateFunc:     validation.StringIsJSON,
 				DiffSuppressFunc: connectionJSONStringsEqual,
+				ConflictsWith:    connectionPluginNames(),
+			},
+			"config_secrets": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: connectionJSONStringsEqual,
+			},
+			"config_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"config_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"config_wo"},
+			},
+			"config_wo_keys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 		},
 	}
+
+	// Typed blocks for the most common plugins, which can be used instead of the raw config
+	for name, blockSchema := range connectionPluginBlockSchemas() {
+		resource.Schema[name] = blockSchema
+	}
+	return resource
 }
 
 func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-	var plugin, connHandle, configString string
-	var config map[string]interface{}
+	var plugin, connHandle, configString, configSecretsString string
+	var config, configSecrets map[string]interface{}
 	var err error
 
 	if value, ok := d.GetOk("handle"); ok {
@@ -112,14 +167,27 @@ func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, meta
 	if value, ok := d.GetOk("config"); ok {
 		configString, config = formatConnectionJSONString(plugin, value.(string))
 	}
+	if value, ok := d.GetOk("config_secrets"); ok {
+		configSecretsString, configSecrets = formatConnectionJSONString(plugin, value.(string))
+	}
+	// config_wo is write-only, so it is only ever available in the raw config
+	configWriteOnly, diags := getConnectionConfigWriteOnly(d, plugin)
+	if diags.HasError() {
+		return diags
+	}
 
 	req := steampipe.CreateConnectionRequest{
 		Handle: connHandle,
 		Plugin: plugin,
 	}
 
-	if config != nil {
-		req.SetConfig(config)
+	var pluginConfig map[string]interface{}
+	if blockName, block := getConnectionPluginBlock(d); blockName != "" {
+		pluginConfig = expandConnectionPluginBlock(blockName, block)
+	}
+	requestConfig := mergeConnectionConfigs(config, pluginConfig, configSecrets, configWriteOnly)
+	if requestConfig != nil {
+		req.SetConfig(requestConfig)
 	}
 
 	client := meta.(*SteampipeClient)
@@ -159,6 +227,10 @@ func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, meta
 	if config != nil {
 		d.Set("config", configString)
 	}
+	if configSecrets != nil {
+		d.Set("config_secrets", configSecretsString)
+	}
+	d.Set("config_wo_keys", connectionConfigKeys(configWriteOnly))
 
 	// If connection is created inside an Organization the id will be of the
 	// format "OrganizationHandle/ConnectionHandle" otherwise "ConnectionHandle"
@@ -168,6 +240,17 @@ func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, meta
 		d.SetId(resp.Handle)
 	}
 
+	if err := checkConnectionPluginVersion(resp.GetPluginVersion(), d.Get("plugin_version_constraint").(string)); err != nil {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: err.Error()})
+	}
+
+	if d.Get("verify").(bool) {
+		status, err := verifyConnection(ctx, client, orgHandle, resp.GetHandle(), plugin, requestConfig)
+		d.Set("verification_status", status)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
 	return diags
 }
 
@@ -232,6 +315,11 @@ func resourceConnectionRead(ctx context.Context, d *schema.ResourceData, meta in
 	d.Set("type", resp.Type)
 	d.Set("plugin", resp.Plugin)
 	d.Set("plugin_version", resp.PluginVersion)
+	// Steampipe Cloud manages the installed plugin version, so an upgrade which no longer satisfies the
+	// constraint is reported as a warning on refresh, the same as in Create and Update
+	if err := checkConnectionPluginVersion(resp.GetPluginVersion(), d.Get("plugin_version_constraint").(string)); err != nil {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: err.Error()})
+	}
 	d.Set("handle", resp.Handle)
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
@@ -242,6 +330,44 @@ func resourceConnectionRead(ctx context.Context, d *schema.ResourceData, meta in
 		d.Set("updated_by", resp.UpdatedBy.Handle)
 	}
 	d.Set("version_id", resp.VersionId)
+	if resp.Config != nil {
+		// The API omits or masks secret values in the config, so merge the config
+		// from the API with the one in state before saving it
+		var stateConfig, stateConfigSecrets map[string]interface{}
+		if value, ok := d.GetOk("config"); ok {
+			_, stateConfig = formatConnectionJSONString(types.SafeString(resp.Plugin), value.(string))
+		}
+		if value, ok := d.GetOk("config_secrets"); ok {
+			_, stateConfigSecrets = formatConnectionJSONString(types.SafeString(resp.Plugin), value.(string))
+		}
+		apiConfig, apiConfigSecrets := splitConnectionConfigSecrets(*resp.Config, stateConfigSecrets)
+		// The values set through config_wo are never saved in state, so they can't be compared either
+		for _, key := range d.Get("config_wo_keys").([]interface{}) {
+			delete(apiConfig, key.(string))
+		}
+
+		if blockName, block := getConnectionPluginBlock(d); blockName != "" {
+			// When a typed plugin block is used the config is read back into the block instead
+			stateBlockConfig := expandConnectionPluginBlock(blockName, block)
+			block := flattenConnectionPluginBlock(blockName, mergeConnectionConfig(stateBlockConfig, apiConfig))
+			if err := d.Set(blockName, []interface{}{block}); err != nil {
+				return diag.Errorf("resourceConnectionRead. Error setting %s: %v", blockName, err)
+			}
+		} else if config := mergeConnectionConfig(stateConfig, apiConfig); len(config) > 0 || stateConfig != nil {
+			configString, err := mapToJSONString(config)
+			if err != nil {
+				return diag.Errorf("resourceConnectionRead. Error formatting connection config: %v", err)
+			}
+			d.Set("config", configString)
+		}
+		if stateConfigSecrets != nil {
+			configSecretsString, err := mapToJSONString(mergeConnectionConfig(stateConfigSecrets, apiConfigSecrets))
+			if err != nil {
+				return diag.Errorf("resourceConnectionRead. Error formatting connection config secrets: %v", err)
+			}
+			d.Set("config_secrets", configSecretsString)
+		}
+	}
 	if separator == ":" {
 		d.SetId(strings.ReplaceAll(id, ":", "/"))
 	}
@@ -252,14 +378,11 @@ func resourceConnectionRead(ctx context.Context, d *schema.ResourceData, meta in
 func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*SteampipeClient)
 
-	var plugin, configString string
+	var plugin, configString, configSecretsString string
 	var r *http.Response
 	var resp steampipe.Connection
 	var err error
-	var config map[string]interface{}
-
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	var config, configSecrets map[string]interface{}
 
 	oldConnectionHandle, newConnectionHandle := d.GetChange("handle")
 	if newConnectionHandle.(string) == "" {
@@ -273,10 +396,23 @@ func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta
 	if value, ok := d.GetOk("config"); ok {
 		configString, config = formatConnectionJSONString(plugin, value.(string))
 	}
+	if value, ok := d.GetOk("config_secrets"); ok {
+		configSecretsString, configSecrets = formatConnectionJSONString(plugin, value.(string))
+	}
+	// config_wo is write-only, so it is only ever available in the raw config
+	configWriteOnly, diags := getConnectionConfigWriteOnly(d, plugin)
+	if diags.HasError() {
+		return diags
+	}
 
 	req := steampipe.UpdateConnectionRequest{Handle: types.String(newConnectionHandle.(string))}
-	if config != nil {
-		req.SetConfig(config)
+	var pluginConfig map[string]interface{}
+	if blockName, block := getConnectionPluginBlock(d); blockName != "" {
+		pluginConfig = expandConnectionPluginBlock(blockName, block)
+	}
+	requestConfig := mergeConnectionConfigs(config, pluginConfig, configSecrets, configWriteOnly)
+	if requestConfig != nil {
+		req.SetConfig(requestConfig)
 	}
 
 	isUser, orgHandle := isUserConnection(d)
@@ -321,6 +457,22 @@ func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta
 	if config != nil {
 		d.Set("config", configString)
 	}
+	if configSecrets != nil {
+		d.Set("config_secrets", configSecretsString)
+	}
+	d.Set("config_wo_keys", connectionConfigKeys(configWriteOnly))
+
+	if err := checkConnectionPluginVersion(resp.GetPluginVersion(), d.Get("plugin_version_constraint").(string)); err != nil {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: err.Error()})
+	}
+
+	if d.Get("verify").(bool) {
+		status, err := verifyConnection(ctx, client, orgHandle, resp.GetHandle(), plugin, requestConfig)
+		d.Set("verification_status", status)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
 	return diags
 }
 
@@ -358,6 +510,134 @@ func resourceConnectionDelete(ctx context.Context, d *schema.ResourceData, meta
 	return diags
 }
 
+func resourceConnectionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("config") || !d.NewValueKnown("config_secrets") {
+		return nil
+	}
+
+	plugin := d.Get("plugin").(string)
+	_, config := formatConnectionJSONString(plugin, d.Get("config").(string))
+	if blockName, block := getConnectionPluginBlock(d); blockName != "" {
+		if d.NewValueKnown("plugin") {
+			isKnown := func(key string) bool {
+				return d.NewValueKnown(fmt.Sprintf("%s.0.%s", blockName, key))
+			}
+			if err := validateConnectionPluginBlock(plugin, blockName, block, isKnown); err != nil {
+				return err
+			}
+		}
+		config = expandConnectionPluginBlock(blockName, block)
+	}
+	_, configSecrets := formatConnectionJSONString(plugin, d.Get("config_secrets").(string))
+	for key := range configSecrets {
+		if _, ok := config[key]; ok {
+			return fmt.Errorf("%q is set in both the connection config and config_secrets", key)
+		}
+	}
+
+	configWriteOnly, diags := getConnectionConfigWriteOnly(d, plugin)
+	if diags.HasError() {
+		return fmt.Errorf("error reading config_wo: %s", diags[0].Summary)
+	}
+	for key := range configWriteOnly {
+		if _, ok := config[key]; ok {
+			return fmt.Errorf("%q is set in both the connection config and config_wo", key)
+		}
+		if _, ok := configSecrets[key]; ok {
+			return fmt.Errorf("%q is set in both config_secrets and config_wo", key)
+		}
+	}
+	return nil
+}
+
+// verifyConnection tests that the connection can be used with its config, returning the status of the test.
+// An error is returned with the plugin's error message if the connection cannot authenticate.
+func verifyConnection(ctx context.Context, client *SteampipeClient, orgHandle, connHandle, plugin string, config map[string]interface{}) (string, error) {
+	var result steampipe.ConnectionTestResult
+	var r *http.Response
+	var err error
+
+	req := steampipe.TestConnectionRequest{Plugin: plugin}
+	if config != nil {
+		req.SetConfig(config)
+	}
+
+	if orgHandle == "" {
+		var actorHandle string
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return "", fmt.Errorf("verifyConnection. getUserHandler error: %v", decodeResponse(r))
+		}
+		result, r, err = client.APIClient.UserConnections.Test(ctx, actorHandle, connHandle).Request(req).Execute()
+	} else {
+		result, r, err = client.APIClient.OrgConnections.Test(ctx, orgHandle, connHandle).Request(req).Execute()
+	}
+	if err != nil {
+		return connectionVerificationFailed, fmt.Errorf("connection %s could not be verified: %v", connHandle, decodeResponse(r))
+	}
+
+	status := types.SafeString(result.Status)
+	if status == "error" || status == connectionVerificationFailed {
+		return status, fmt.Errorf("connection %s could not be verified: %s", connHandle, types.SafeString(result.Reason))
+	}
+	return status, nil
+}
+
+// checkConnectionPluginVersion returns an error if the installed plugin version does not satisfy the constraint.
+// Versions which are not semantic versions, e.g. latest, are not checked.
+func checkConnectionPluginVersion(installedVersion, constraint string) error {
+	if installedVersion == "" || constraint == "" {
+		return nil
+	}
+	v, err := version.NewVersion(installedVersion)
+	if err != nil {
+		return nil
+	}
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return err
+	}
+	if !constraints.Check(v) {
+		return fmt.Errorf("the installed plugin version %s does not satisfy the plugin_version_constraint %q", installedVersion, constraint)
+	}
+	return nil
+}
+
+func validateVersionConstraint(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := version.NewConstraint(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid version constraint, got %q: %v", key, val, err))
+	}
+	return
+}
+
+// rawConfigReader is implemented by both schema.ResourceData and schema.ResourceDiff
+type rawConfigReader interface {
+	GetRawConfigAt(valPath cty.Path) (cty.Value, diag.Diagnostics)
+}
+
+// getConnectionConfigWriteOnly returns the config set through config_wo, which is never saved in plan or state
+func getConnectionConfigWriteOnly(d rawConfigReader, plugin string) (map[string]interface{}, diag.Diagnostics) {
+	value, diags := d.GetRawConfigAt(cty.GetAttrPath("config_wo"))
+	if diags.HasError() {
+		return nil, diags
+	}
+	if !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return nil, nil
+	}
+	_, config := formatConnectionJSONString(plugin, value.AsString())
+	return config, nil
+}
+
+// connectionConfigKeys returns the sorted keys of a connection config
+func connectionConfigKeys(config map[string]interface{}) []string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // config is a json string
 // apply standard formatting to old and new data then compare
 func connectionJSONStringsEqual(k, old, new string, d *schema.ResourceData) bool {
@@ -393,3 +673,64 @@ func formatConnectionJSONString(plugin, body string) (string, map[string]interfa
 	}
 	return body, data
 }
+
+// mergeConnectionConfig returns the config returned by the API, with the values for any keys that the API
+// omits or masks (e.g. secret keys) taken from the config in state instead, since those can never be compared
+func mergeConnectionConfig(stateConfig, apiConfig map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{}
+	for key, value := range apiConfig {
+		if stateValue, ok := stateConfig[key]; ok && isMaskedConnectionConfigValue(value) {
+			config[key] = stateValue
+			continue
+		}
+		config[key] = value
+	}
+	for key, stateValue := range stateConfig {
+		if _, ok := apiConfig[key]; !ok {
+			config[key] = stateValue
+		}
+	}
+	return config
+}
+
+// isMaskedConnectionConfigValue checks whether a config value has been masked by the API, e.g. "********"
+func isMaskedConnectionConfigValue(value interface{}) bool {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return false
+	}
+	return strings.Trim(s, "*") == ""
+}
+
+// mergeConnectionConfigs returns the config to be sent to the API, made up of the keys in all of the given configs,
+// i.e. config or the typed plugin block, config_secrets and config_wo
+func mergeConnectionConfigs(configs ...map[string]interface{}) map[string]interface{} {
+	var requestConfig map[string]interface{}
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		if requestConfig == nil {
+			requestConfig = map[string]interface{}{}
+		}
+		for key, value := range config {
+			requestConfig[key] = value
+		}
+	}
+	return requestConfig
+}
+
+// splitConnectionConfigSecrets splits the config returned by the API into the keys managed through config and the
+// keys managed through config_secrets, so that secret values never end up in config
+func splitConnectionConfigSecrets(apiConfig, configSecrets map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	config := map[string]interface{}{}
+	secrets := map[string]interface{}{}
+	for key, value := range apiConfig {
+		if _, ok := configSecrets[key]; ok {
+			secrets[key] = value
+		} else {
+			config[key] = value
+		}
+	}
+	return config, secrets
+}
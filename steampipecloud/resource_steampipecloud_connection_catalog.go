@@ -0,0 +1,201 @@
+package steampipecloud
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// connectionPluginCatalogJSON is the bundled catalog of the connection config arguments published by each plugin.
+// Plugins which are not in the catalog are not validated, and arguments which are not in the catalog are only
+// warned about, since the catalog may lag behind the plugins.
+//
+//go:embed connection_plugin_catalog.json
+var connectionPluginCatalogJSON []byte
+
+type connectionPluginCatalogArgument struct {
+	// Type is one of string, number, bool or list(string)
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// Sensitive arguments are expected in config_secrets or config_wo rather than config
+	Sensitive bool `json:"sensitive"`
+}
+
+type connectionPluginCatalogEntry struct {
+	Arguments map[string]connectionPluginCatalogArgument `json:"arguments"`
+}
+
+// connectionPluginCatalogErr is reported when the provider is configured
+var connectionPluginCatalog, connectionPluginCatalogErr = loadConnectionPluginCatalog(connectionPluginCatalogJSON)
+
+func loadConnectionPluginCatalog(data []byte) (map[string]connectionPluginCatalogEntry, error) {
+	catalog := map[string]connectionPluginCatalogEntry{}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid connection plugin catalog: %v", err)
+	}
+	return catalog, nil
+}
+
+// connectionPluginName returns the short name of a plugin, e.g. aws for turbot/aws or aws@0.80
+func connectionPluginName(plugin string) string {
+	return strings.TrimPrefix(strings.Split(plugin, "@")[0], "turbot/")
+}
+
+// getConnectionPluginCatalogEntry returns the catalog entry for a plugin, accepting names such as
+// aws, turbot/aws or turbot/aws@latest
+func getConnectionPluginCatalogEntry(plugin string) (connectionPluginCatalogEntry, bool) {
+	entry, ok := connectionPluginCatalog[connectionPluginName(plugin)]
+	return entry, ok
+}
+
+// The connection config attributes which are merged into the config sent to the API
+var connectionConfigAttributes = []string{"config", "config_secrets", "config_wo"}
+
+// validateConnectionConfigRaw validates the JSON connection config against the plugin catalog during plan.
+// Values which are not yet known are skipped, and the check for required arguments is only made once
+// every part of the config is known.
+func validateConnectionConfigRaw(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if !req.RawConfig.IsKnown() || req.RawConfig.IsNull() {
+		return
+	}
+	pluginValue := req.RawConfig.GetAttr("plugin")
+	if !pluginValue.IsKnown() || pluginValue.IsNull() {
+		return
+	}
+
+	configs := map[string]map[string]interface{}{}
+	checkRequired := true
+	for _, attribute := range connectionConfigAttributes {
+		value := req.RawConfig.GetAttr(attribute)
+		if !value.IsKnown() {
+			checkRequired = false
+			continue
+		}
+		if value.IsNull() {
+			continue
+		}
+		config := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(value.AsString()), &config); err != nil {
+			// invalid JSON is reported by the attribute validation
+			checkRequired = false
+			continue
+		}
+		configs[attribute] = config
+	}
+
+	// The typed plugin blocks enforce their own required arguments
+	for _, name := range connectionPluginNames() {
+		if block := req.RawConfig.GetAttr(name); !block.IsKnown() || !block.IsNull() && block.LengthInt() > 0 {
+			checkRequired = false
+		}
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, validateConnectionConfigCatalog(pluginValue.AsString(), configs, checkRequired)...)
+}
+
+// validateConnectionConfigCatalog checks the connection config, keyed by the attribute it was set in, against
+// the catalog entry for the plugin. It warns about unknown keys, and reports values of the wrong type and missing
+// required keys as errors.
+func validateConnectionConfigCatalog(plugin string, configs map[string]map[string]interface{}, checkRequired bool) diag.Diagnostics {
+	// an invalid catalog is reported when the provider is configured
+	if connectionPluginCatalogErr != nil {
+		return nil
+	}
+	entry, ok := getConnectionPluginCatalogEntry(plugin)
+	if !ok {
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	set := map[string]bool{}
+	for _, attribute := range connectionConfigAttributes {
+		config := configs[attribute]
+		for _, key := range connectionConfigKeys(config) {
+			set[key] = true
+			argument, ok := entry.Arguments[key]
+			if !ok {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Warning,
+					Summary:       "Unrecognized connection config argument",
+					Detail:        fmt.Sprintf("%q is not a connection config argument of the %s plugin known to this provider. It is passed to the plugin unchecked.", key, plugin),
+					AttributePath: cty.GetAttrPath(attribute),
+				})
+				continue
+			}
+			if !isConnectionConfigValueOfType(config[key], argument.Type) {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid connection config argument type",
+					Detail:        fmt.Sprintf("The %q argument of the %s plugin must be of type %s.", key, plugin, argument.Type),
+					AttributePath: cty.GetAttrPath(attribute),
+				})
+			}
+		}
+	}
+
+	if !checkRequired {
+		return diags
+	}
+	var keys []string
+	for key := range entry.Arguments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if argument := entry.Arguments[key]; argument.Required && !set[key] {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Missing required connection config argument",
+				Detail:        fmt.Sprintf("The %q argument is required by the %s plugin.", key, plugin),
+				AttributePath: cty.GetAttrPath(missingConnectionConfigAttribute(argument, configs)),
+			})
+		}
+	}
+	return diags
+}
+
+// missingConnectionConfigAttribute returns the attribute a missing argument is expected in. Sensitive arguments
+// belong in config_wo when it is used, otherwise in config_secrets.
+func missingConnectionConfigAttribute(argument connectionPluginCatalogArgument, configs map[string]map[string]interface{}) string {
+	if !argument.Sensitive {
+		return "config"
+	}
+	if _, ok := configs["config_wo"]; ok {
+		return "config_wo"
+	}
+	return "config_secrets"
+}
+
+func isConnectionConfigValueOfType(value interface{}, valueType string) bool {
+	switch valueType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "list(string)":
+		items, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	// types which the provider does not know about are not checked
+	return true
+}
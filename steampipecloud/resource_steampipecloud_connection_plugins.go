@@ -0,0 +1,227 @@
+package steampipecloud
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// connectionPluginField describes a single argument of a typed plugin block on steampipecloud_connection
+type connectionPluginField struct {
+	Type      schema.ValueType
+	Required  bool
+	Sensitive bool
+	// ElemType is the type of the elements for TypeList fields
+	ElemType schema.ValueType
+	// AllowedValues restricts the value (or the list elements) to a set of values
+	AllowedValues []string
+	// RequiredWith lists the fields of the same block that must be set when this field is set
+	RequiredWith []string
+	// IsJSON indicates that the value must be a JSON string, e.g. a GCP service account key
+	IsJSON bool
+}
+
+// connectionPlugins holds the typed blocks for the most common plugins, keyed by plugin name. The block
+// arguments map one-to-one to the keys of the plugin's connection config.
+var connectionPlugins = map[string]map[string]connectionPluginField{
+	"aws": {
+		"regions":                  {Type: schema.TypeList, ElemType: schema.TypeString},
+		"default_region":           {Type: schema.TypeString},
+		"access_key":               {Type: schema.TypeString, RequiredWith: []string{"secret_key"}},
+		"secret_key":               {Type: schema.TypeString, Sensitive: true, RequiredWith: []string{"access_key"}},
+		"session_token":            {Type: schema.TypeString, Sensitive: true, RequiredWith: []string{"access_key", "secret_key"}},
+		"role_arn":                 {Type: schema.TypeString, RequiredWith: []string{"external_id"}},
+		"external_id":              {Type: schema.TypeString, RequiredWith: []string{"role_arn"}},
+		"ignore_error_codes":       {Type: schema.TypeList, ElemType: schema.TypeString},
+		"max_error_retry_attempts": {Type: schema.TypeInt},
+		"min_error_retry_delay":    {Type: schema.TypeInt},
+	},
+	"azure": {
+		"tenant_id":          {Type: schema.TypeString, Required: true},
+		"subscription_id":    {Type: schema.TypeString, Required: true},
+		"client_id":          {Type: schema.TypeString, Required: true},
+		"client_secret":      {Type: schema.TypeString, Required: true, Sensitive: true},
+		"environment":        {Type: schema.TypeString, AllowedValues: []string{"AZUREPUBLICCLOUD", "AZURECHINACLOUD", "AZUREUSGOVERNMENTCLOUD", "AZUREGERMANCLOUD"}},
+		"ignore_error_codes": {Type: schema.TypeList, ElemType: schema.TypeString},
+	},
+	"gcp": {
+		"project":                     {Type: schema.TypeString},
+		"credentials":                 {Type: schema.TypeString, Required: true, Sensitive: true, IsJSON: true},
+		"impersonate_service_account": {Type: schema.TypeString},
+		"ignore_error_codes":          {Type: schema.TypeList, ElemType: schema.TypeString},
+	},
+	"github": {
+		"token":           {Type: schema.TypeString, Sensitive: true},
+		"base_url":        {Type: schema.TypeString},
+		"app_id":          {Type: schema.TypeString, RequiredWith: []string{"installation_id", "private_key"}},
+		"installation_id": {Type: schema.TypeString, RequiredWith: []string{"app_id", "private_key"}},
+		"private_key":     {Type: schema.TypeString, Sensitive: true, RequiredWith: []string{"app_id", "installation_id"}},
+	},
+	"kubernetes": {
+		"config_path":            {Type: schema.TypeString},
+		"config_context":         {Type: schema.TypeString},
+		"custom_resource_tables": {Type: schema.TypeList, ElemType: schema.TypeString},
+		"source_types":           {Type: schema.TypeList, ElemType: schema.TypeString, AllowedValues: []string{"deployed", "helm", "manifest"}},
+		"manifest_file_paths":    {Type: schema.TypeList, ElemType: schema.TypeString},
+	},
+	"slack": {
+		"token": {Type: schema.TypeString, Required: true, Sensitive: true},
+	},
+}
+
+// connectionPluginNames returns the sorted names of the plugins that have a typed block
+func connectionPluginNames() []string {
+	var names []string
+	for name := range connectionPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectionPluginBlockSchemas returns the schema for the typed plugin blocks, each of which conflicts
+// with the raw config and with the blocks for the other plugins
+func connectionPluginBlockSchemas() map[string]*schema.Schema {
+	schemas := map[string]*schema.Schema{}
+	names := connectionPluginNames()
+	for _, name := range names {
+		conflictsWith := []string{"config"}
+		for _, other := range names {
+			if other != name {
+				conflictsWith = append(conflictsWith, other)
+			}
+		}
+
+		fields := map[string]*schema.Schema{}
+		for key, field := range connectionPlugins[name] {
+			fields[key] = connectionPluginFieldSchema(field)
+		}
+
+		schemas[name] = &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: conflictsWith,
+			Elem:          &schema.Resource{Schema: fields},
+		}
+	}
+	return schemas
+}
+
+func connectionPluginFieldSchema(field connectionPluginField) *schema.Schema {
+	s := &schema.Schema{
+		Type:      field.Type,
+		Required:  field.Required,
+		Optional:  !field.Required,
+		Sensitive: field.Sensitive,
+	}
+	var validateFunc schema.SchemaValidateFunc
+	if len(field.AllowedValues) > 0 {
+		validateFunc = validation.StringInSlice(field.AllowedValues, false)
+	}
+	if field.IsJSON {
+		validateFunc = validation.StringIsJSON
+	}
+	if field.Type == schema.TypeList {
+		s.Elem = &schema.Schema{Type: field.ElemType, ValidateFunc: validateFunc}
+	} else {
+		s.ValidateFunc = validateFunc
+	}
+	return s
+}
+
+// getConnectionPluginBlock returns the typed block that is set on the connection, if any
+func getConnectionPluginBlock(d interface{ Get(string) interface{} }) (string, map[string]interface{}) {
+	for _, name := range connectionPluginNames() {
+		if blocks, ok := d.Get(name).([]interface{}); ok && len(blocks) > 0 {
+			block, _ := blocks[0].(map[string]interface{})
+			if block == nil {
+				block = map[string]interface{}{}
+			}
+			return name, block
+		}
+	}
+	return "", nil
+}
+
+// expandConnectionPluginBlock converts a typed plugin block into the connection config sent to the API.
+// Unset arguments, i.e. those with zero values, are left out so the plugin defaults apply.
+func expandConnectionPluginBlock(plugin string, block map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{}
+	for key, field := range connectionPlugins[plugin] {
+		value, ok := block[key]
+		if !ok || value == nil {
+			continue
+		}
+		switch field.Type {
+		case schema.TypeList:
+			if items := value.([]interface{}); len(items) > 0 {
+				config[key] = items
+			}
+		case schema.TypeInt:
+			if value.(int) != 0 {
+				config[key] = value
+			}
+		case schema.TypeBool:
+			if value.(bool) {
+				config[key] = value
+			}
+		default:
+			if value.(string) != "" {
+				config[key] = value
+			}
+		}
+	}
+	return config
+}
+
+// flattenConnectionPluginBlock converts the connection config returned by the API into a typed plugin block.
+// Keys which the plugin block does not define are ignored.
+func flattenConnectionPluginBlock(plugin string, config map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{}
+	for key, field := range connectionPlugins[plugin] {
+		value, ok := config[key]
+		if !ok || value == nil {
+			continue
+		}
+		switch field.Type {
+		case schema.TypeInt:
+			// numbers in the JSON config are decoded as float64
+			if number, ok := value.(float64); ok {
+				value = int(number)
+			}
+		}
+		block[key] = value
+	}
+	return block
+}
+
+// validateConnectionPluginBlock checks the typed plugin block against the plugin of the connection, which may
+// be given as e.g. turbot/aws or aws@0.80, and checks the dependencies between its arguments. Arguments whose
+// values are not yet known are skipped.
+func validateConnectionPluginBlock(plugin, blockName string, block map[string]interface{}, isKnown func(key string) bool) error {
+	if connectionPluginName(plugin) != blockName {
+		return fmt.Errorf("the %s block can only be used with the %q plugin, got %q", blockName, blockName, plugin)
+	}
+
+	config := expandConnectionPluginBlock(blockName, block)
+	fields := connectionPlugins[blockName]
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := config[key]; !ok {
+			continue
+		}
+		for _, requiredKey := range fields[key].RequiredWith {
+			if _, ok := config[requiredKey]; !ok && isKnown(requiredKey) {
+				return fmt.Errorf("%s.0.%s: %q must be set when %q is set", blockName, requiredKey, requiredKey, key)
+			}
+		}
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ func resourceOrganization() *schema.Resource {
 		UpdateContext: resourceOrganizationUpdate,
 		DeleteContext: resourceOrganizationDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughByID,
 		},
 		Schema: map[string]*schema.Schema{
 			"handle": {
@@ -194,10 +194,8 @@ func resourceOrganizationWorkspaceMemberRead(ctx context.Context, d *schema.Reso
 
 	orgWorkspaceMemberDetails, r, err := client.APIClient.OrgWorkspaceMembers.Get(context.Background(), org, workspace, user).Execute()
 	if err != nil {
-		if r.StatusCode == 404 {
-			log.Printf("\n[WARN] Member (%s) not found in workspace (%s) of organization (%s)", user, workspace, org)
-			d.SetId("")
-			return nil
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Member (%s) not found in workspace (%s) of organization (%s)", user, workspace, org))
 		}
 		return diag.Errorf("error reading %s/%s.\nerr: %s", org, user, decodeResponse(r))
 	}
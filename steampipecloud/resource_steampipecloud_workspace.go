@@ -21,8 +21,9 @@ func resourceWorkspace() *schema.Resource {
 		ReadContext:   resourceWorkspaceRead,
 		UpdateContext: resourceWorkspaceUpdate,
 		DeleteContext: resourceWorkspaceDelete,
+		CustomizeDiff: forceNewOnOrganizationChange,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughByID,
 		},
 		Schema: map[string]*schema.Schema{
 			"handle": {
@@ -34,6 +35,7 @@ func resourceWorkspace() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 			},
 			"workspace_id": {
 				Type:     schema.TypeString,
@@ -2,15 +2,20 @@ package steampipecloud
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
 	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
@@ -20,6 +25,7 @@ func resourceWorkspaceAggregator() *schema.Resource {
 		ReadContext:   resourceWorkspaceAggregatorRead,
 		UpdateContext: resourceWorkspaceAggregatorUpdate,
 		DeleteContext: resourceWorkspaceAggregatorDelete,
+		CustomizeDiff: resourceWorkspaceAggregatorCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -48,10 +54,15 @@ func resourceWorkspaceAggregator() *schema.Resource {
 				Required: true,
 			},
 			"connections": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Required: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"matched_connections": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"created_at": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -102,10 +113,11 @@ func resourceWorkspaceAggregatorCreate(ctx context.Context, d *schema.ResourceDa
 	workspaceHandle := d.Get("workspace").(string)
 	aggregatorHandle := d.Get("handle").(string)
 	plugin := d.Get("plugin").(string)
-	connections, err := convertToStringArray(d.Get("connections").([]interface{}))
+	connections, err := convertToStringArray(d.Get("connections").(*schema.Set).List())
 	if err != nil {
 		return diag.Errorf("resourceWorkspaceAggregatorCreate.connections error  %v", err.Error())
 	}
+	sort.Strings(connections)
 
 	log.Printf("\n[DEBUG] Workspace Handle: %v", workspaceHandle)
 	log.Printf("\n[DEBUG] Aggregator Handle: %v", aggregatorHandle)
@@ -152,6 +164,12 @@ func resourceWorkspaceAggregatorCreate(ctx context.Context, d *schema.ResourceDa
 	d.Set("organization", orgHandle)
 	d.Set("workspace", workspaceHandle)
 
+	matchDiags := setAggregatorMatchedConnections(ctx, client, d, orgHandle, workspaceHandle, resp)
+	if matchDiags.HasError() {
+		return matchDiags
+	}
+	diags = append(diags, matchDiags...)
+
 	// If an aggregator is created for a workspace inside an organization then the ID will be of the
 	// format "OrganizationHandle/WorkspaceHandle/AggregatorHandle" otherwise "WorkspaceHandle/AggregatorHandle".
 	if userHandle == "" {
@@ -205,6 +223,9 @@ func resourceWorkspaceAggregatorRead(ctx context.Context, d *schema.ResourceData
 
 	// Error check
 	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace aggregator (%s) not found in workspace (%s)", aggregatorHandle, workspaceHandle))
+		}
 		return diag.Errorf("error getting workspace aggregator: %v", decodeResponse(r))
 	}
 	log.Printf("\n[DEBUG] Aggregator: %s received for Workspace: %s", resp.Id, workspaceHandle)
@@ -228,6 +249,12 @@ func resourceWorkspaceAggregatorRead(ctx context.Context, d *schema.ResourceData
 	d.Set("organization", orgHandle)
 	d.Set("workspace", workspaceHandle)
 
+	matchDiags := setAggregatorMatchedConnections(ctx, client, d, orgHandle, workspaceHandle, resp)
+	if matchDiags.HasError() {
+		return matchDiags
+	}
+	diags = append(diags, matchDiags...)
+
 	// If an aggregator is created for a workspace inside an organization then the ID will be of the
 	// format "OrganizationHandle/WorkspaceHandle/AggregatorHandle" otherwise "WorkspaceHandle/AggregatorHandle".
 	if userHandle == "" {
@@ -254,10 +281,11 @@ func resourceWorkspaceAggregatorUpdate(ctx context.Context, d *schema.ResourceDa
 	if !ok {
 		return diag.Errorf("resourceWorkspaceAggregatorCreate.handle error : invalid value passed for aggregator handle")
 	}
-	connections, err := convertToStringArray(d.Get("connections").([]interface{}))
+	connections, err := convertToStringArray(d.Get("connections").(*schema.Set).List())
 	if err != nil {
 		return diag.Errorf("resourceWorkspaceAggregatorCreate.connections error  %v", err.Error())
 	}
+	sort.Strings(connections)
 
 	log.Printf("\n[DEBUG] Workspace Handle: %v", workspaceHandle)
 	log.Printf("\n[DEBUG] Aggregator Handle: %v", oldAggregatorHandle)
@@ -303,6 +331,12 @@ func resourceWorkspaceAggregatorUpdate(ctx context.Context, d *schema.ResourceDa
 	d.Set("organization", orgHandle)
 	d.Set("workspace", workspaceHandle)
 
+	matchDiags := setAggregatorMatchedConnections(ctx, client, d, orgHandle, workspaceHandle, resp)
+	if matchDiags.HasError() {
+		return matchDiags
+	}
+	diags = append(diags, matchDiags...)
+
 	// If an aggregator is created for a workspace inside an organization then the ID will be of the
 	// format "OrganizationHandle/WorkspaceHandle/AggregatorHandle" otherwise "WorkspaceHandle/AggregatorHandle".
 	if userHandle == "" {
@@ -360,3 +394,106 @@ func resourceWorkspaceAggregatorDelete(ctx context.Context, d *schema.ResourceDa
 
 	return diags
 }
+
+// resourceWorkspaceAggregatorCustomizeDiff checks during plan that the attached connections named in the aggregator
+// use the plugin of the aggregator. Connections which are not attached yet are skipped, since they may be attached
+// in the same apply, and the check is skipped while the workspace doesn't exist.
+func resourceWorkspaceAggregatorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("connections") || !d.NewValueKnown("plugin") || !d.NewValueKnown("workspace") {
+		return nil
+	}
+	var orgHandle string
+	if rawConfig := d.GetRawConfig(); rawConfig.IsKnown() && !rawConfig.IsNull() {
+		org := rawConfig.GetAttr("organization")
+		if !org.IsKnown() {
+			return nil
+		}
+		if !org.IsNull() {
+			orgHandle = org.AsString()
+		}
+	}
+
+	client := meta.(*SteampipeClient)
+	workspaceHandle := d.Get("workspace").(string)
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		if isNotFound(r) {
+			return nil
+		}
+		return fmt.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	connections, err := convertToStringArray(d.Get("connections").(*schema.Set).List())
+	if err != nil {
+		return err
+	}
+	_, _, err = matchAggregatorConnections(d.Get("plugin").(string), connections, workspaceConnectionsByHandle(attached))
+	return err
+}
+
+// setAggregatorMatchedConnections sets the handles of the attached connections which the aggregator connections
+// match, and warns about connection handles which are not attached to the workspace, e.g. mistyped handles
+func setAggregatorMatchedConnections(ctx context.Context, client *SteampipeClient, d *schema.ResourceData, orgHandle, workspaceHandle string, aggregator steampipe.WorkspaceAggregator) diag.Diagnostics {
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		return diag.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+	// Connections which use a different plugin are ignored here, since they are reported during plan
+	matched, notAttached, _ := matchAggregatorConnections(aggregator.Plugin, aggregator.Connections, workspaceConnectionsByHandle(attached))
+	d.Set("matched_connections", matched)
+
+	var diags diag.Diagnostics
+	for _, handle := range notAttached {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Aggregator connection not attached",
+			Detail:        fmt.Sprintf("Connection %s of aggregator %s is not attached to workspace %s, so the aggregator does not include it.", handle, aggregator.Handle, workspaceHandle),
+			AttributePath: cty.GetAttrPath("connections"),
+		})
+	}
+	return diags
+}
+
+// matchAggregatorConnections resolves the aggregator connections, which may be glob patterns such as aws_*, against
+// the connections attached to the workspace. Patterns only match connections of the aggregator plugin, and an error
+// names any attached connection handle that uses a different plugin. Handles which aren't attached are returned
+// separately, since they may be attached in the same apply.
+func matchAggregatorConnections(plugin string, connections []string, attached map[string]steampipe.WorkspaceConn) ([]string, []string, error) {
+	matched := map[string]bool{}
+	var notAttached []string
+	var errs []error
+	for _, pattern := range connections {
+		if !strings.ContainsAny(pattern, "*?[") {
+			association, ok := attached[pattern]
+			if !ok {
+				notAttached = append(notAttached, pattern)
+				continue
+			}
+			if connectionPlugin := types.SafeString(association.Connection.Plugin); connectionPlugin != plugin {
+				errs = append(errs, fmt.Errorf("connection %s uses the %s plugin, not %s", pattern, connectionPlugin, plugin))
+				continue
+			}
+			matched[pattern] = true
+			continue
+		}
+		for handle, association := range attached {
+			if types.SafeString(association.Connection.Plugin) != plugin {
+				continue
+			}
+			if ok, err := path.Match(pattern, handle); err != nil {
+				errs = append(errs, fmt.Errorf("invalid connection pattern %s: %v", pattern, err))
+				break
+			} else if ok {
+				matched[handle] = true
+			}
+		}
+	}
+
+	handles := []string{}
+	for handle := range matched {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+	sort.Strings(notAttached)
+	return handles, notAttached, errors.Join(errs...)
+}
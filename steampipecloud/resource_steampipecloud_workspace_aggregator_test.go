@@ -9,6 +9,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
 // test suites
@@ -85,6 +88,30 @@ func testAccUserWorkspaceAggregatorUpdateConfig(workspaceHandle, aggregatorHandl
 	}`, workspaceHandle, aggregatorHandle, plugin, connections)
 }
 
+func TestMatchAggregatorConnections(t *testing.T) {
+	attached := workspaceConnectionsByHandle([]steampipe.WorkspaceConn{
+		{Connection: &steampipe.Connection{Handle: "aws_dev", Plugin: types.String("aws")}},
+		{Connection: &steampipe.Connection{Handle: "aws_prod", Plugin: types.String("aws")}},
+		{Connection: &steampipe.Connection{Handle: "aws_gcp", Plugin: types.String("gcp")}},
+		{Connection: &steampipe.Connection{Handle: "azure", Plugin: types.String("azure")}},
+	})
+
+	matched, notAttached, err := matchAggregatorConnections("aws", []string{"aws_*"}, attached)
+	require.NoError(t, err)
+	require.Equal(t, []string{"aws_dev", "aws_prod"}, matched)
+	require.Empty(t, notAttached)
+
+	matched, _, err = matchAggregatorConnections("aws", []string{"aws_dev", "aws_d*", "gcp_*"}, attached)
+	require.NoError(t, err)
+	require.Equal(t, []string{"aws_dev"}, matched)
+
+	// connections which are not attached yet may be attached in the same apply, so they are not an error
+	matched, notAttached, err = matchAggregatorConnections("aws", []string{"aws_dev", "aws_test", "azure"}, attached)
+	require.EqualError(t, err, "connection azure uses the azure plugin, not aws")
+	require.Equal(t, []string{"aws_dev"}, matched)
+	require.Equal(t, []string{"aws_test"}, notAttached)
+}
+
 func testAccCheckWorkspaceAggregatorExists(workspaceHandle string) resource.TestCheckFunc {
 	ctx := context.Background()
 	return func(s *terraform.State) error {
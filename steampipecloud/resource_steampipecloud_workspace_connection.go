@@ -21,7 +21,7 @@ func resourceWorkspaceConnection() *schema.Resource {
 		UpdateContext: resourceWorkspaceConnectionUpdate,
 		DeleteContext: resourceWorkspaceConnectionDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughByID,
 		},
 		Schema: map[string]*schema.Schema{
 			"connection_handle": {
@@ -273,13 +273,8 @@ func resourceWorkspaceConnectionRead(ctx context.Context, d *schema.ResourceData
 	}
 
 	if err != nil {
-		if r.StatusCode == 404 {
-			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Warning,
-				Summary:  fmt.Sprintf("Association (%s) not found", resp.Id),
-			})
-			d.SetId("")
-			return diags
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Connection (%s) not attached to workspace (%s)", connectionHandle, workspaceHandle))
 		}
 		return diag.Errorf("resourceWorkspaceConnectionRead. Get workspace connection association error: %v", decodeResponse(r))
 	}
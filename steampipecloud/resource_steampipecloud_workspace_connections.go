@@ -0,0 +1,238 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func resourceWorkspaceConnections() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWorkspaceConnectionsCreate,
+		ReadContext:   resourceWorkspaceConnectionsRead,
+		UpdateContext: resourceWorkspaceConnectionsUpdate,
+		DeleteContext: resourceWorkspaceConnectionsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughByID,
+		},
+		Schema: map[string]*schema.Schema{
+			"workspace_handle": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z0-9]{1,23}$`), "Handle must be between 1 and 23 characters, and may only contain alphanumeric characters."),
+			},
+			"organization": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"connection_handles": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z][a-z0-9_]{0,37}[a-z0-9]?$`), "Handle must be between 1 and 39 characters, and may only contain alphanumeric characters or single underscores, cannot start with a number or underscore and cannot end with an underscore."),
+				},
+			},
+			"connection_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceWorkspaceConnectionsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	diags := syncWorkspaceConnections(ctx, meta.(*SteampipeClient), orgHandle, workspaceHandle, d.Get("connection_handles").(*schema.Set), nil)
+	if diags.HasError() {
+		return diags
+	}
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	if orgHandle != "" {
+		d.SetId(fmt.Sprintf("%s/%s", orgHandle, workspaceHandle))
+	} else {
+		d.SetId(workspaceHandle)
+	}
+
+	return append(diags, resourceWorkspaceConnectionsRead(ctx, d, meta)...)
+}
+
+func resourceWorkspaceConnectionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	var orgHandle, workspaceHandle string
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	idParts := strings.Split(d.Id(), "/")
+	if len(idParts) == 2 {
+		orgHandle = idParts[0]
+		workspaceHandle = idParts[1]
+	} else if len(idParts) == 1 {
+		workspaceHandle = idParts[0]
+	} else {
+		return diag.Errorf("unexpected format of ID (%q), expected <workspace-handle> or <organization-handle>/<workspace-handle>", d.Id())
+	}
+
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		if isNotFound(r) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Workspace (%s) not found", workspaceHandle),
+			})
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	// Any connection attached outside of Terraform shows as drift, since the set of connections is authoritative
+	var handles []string
+	connectionIds := map[string]interface{}{}
+	for handle, association := range workspaceConnectionsByHandle(attached) {
+		handles = append(handles, handle)
+		connectionIds[handle] = association.ConnectionId
+	}
+	sort.Strings(handles)
+
+	d.Set("workspace_handle", workspaceHandle)
+	d.Set("organization", orgHandle)
+	if err := d.Set("connection_handles", handles); err != nil {
+		return diag.Errorf("error setting connection_handles: %v", err)
+	}
+	d.Set("connection_ids", connectionIds)
+
+	return diags
+}
+
+func resourceWorkspaceConnectionsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	diags := syncWorkspaceConnections(ctx, meta.(*SteampipeClient), orgHandle, workspaceHandle, d.Get("connection_handles").(*schema.Set), nil)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceWorkspaceConnectionsRead(ctx, d, meta)...)
+}
+
+func resourceWorkspaceConnectionsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	// Only the connections managed by this resource are detached
+	diags := syncWorkspaceConnections(ctx, meta.(*SteampipeClient), orgHandle, workspaceHandle, schema.NewSet(schema.HashString, nil), d.Get("connection_handles").(*schema.Set))
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId("")
+	return diags
+}
+
+// syncWorkspaceConnections attaches and detaches connections so that exactly the desired connections are
+// attached to the workspace. If detachable is not nil, only the connections in it are detached.
+func syncWorkspaceConnections(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle string, desired, detachable *schema.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		return diag.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+	attachedByHandle := workspaceConnectionsByHandle(attached)
+
+	toAttach, toDetach := diffWorkspaceConnections(attachedByHandle, desired)
+	if detachable != nil {
+		var handles []string
+		for _, handle := range toDetach {
+			if detachable.Contains(handle) {
+				handles = append(handles, handle)
+			}
+		}
+		toDetach = handles
+	}
+
+	var actorHandle string
+	if orgHandle == "" {
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return diag.Errorf("syncWorkspaceConnections. getUserHandler error: %v", decodeResponse(r))
+		}
+	}
+
+	for _, handle := range toAttach {
+		log.Printf("\n[DEBUG] Attaching connection %s to workspace %s", handle, workspaceHandle)
+		req := steampipe.CreateWorkspaceConnRequest{ConnectionHandle: handle}
+		if orgHandle == "" {
+			_, r, err = client.APIClient.UserWorkspaceConnectionAssociations.Create(ctx, actorHandle, workspaceHandle).Request(req).Execute()
+		} else {
+			_, r, err = client.APIClient.OrgWorkspaceConnectionAssociations.Create(ctx, orgHandle, workspaceHandle).Request(req).Execute()
+		}
+		if err != nil {
+			return append(diags, diag.Errorf("error attaching connection %s to workspace %s: %v", handle, workspaceHandle, decodeResponse(r))...)
+		}
+	}
+
+	for _, handle := range toDetach {
+		log.Printf("\n[DEBUG] Detaching connection %s from workspace %s", handle, workspaceHandle)
+		if orgHandle == "" {
+			_, r, err = client.APIClient.UserWorkspaceConnectionAssociations.Delete(ctx, actorHandle, workspaceHandle, handle).Execute()
+		} else {
+			_, r, err = client.APIClient.OrgWorkspaceConnectionAssociations.Delete(ctx, orgHandle, workspaceHandle, handle).Execute()
+		}
+		if err != nil && !isNotFound(r) {
+			return append(diags, diag.Errorf("error detaching connection %s from workspace %s: %v", handle, workspaceHandle, decodeResponse(r))...)
+		}
+	}
+
+	return diags
+}
+
+// workspaceConnectionsByHandle returns the workspace connection associations keyed by connection handle.
+// Aggregators are left out, since they are managed by steampipecloud_workspace_aggregator.
+func workspaceConnectionsByHandle(associations []steampipe.WorkspaceConn) map[string]steampipe.WorkspaceConn {
+	byHandle := map[string]steampipe.WorkspaceConn{}
+	for _, association := range associations {
+		if association.Connection == nil || types.SafeString(association.Connection.Type) == "aggregator" {
+			continue
+		}
+		byHandle[association.Connection.Handle] = association
+	}
+	return byHandle
+}
+
+// diffWorkspaceConnections returns the sorted handles of the connections to attach and detach
+func diffWorkspaceConnections(attached map[string]steampipe.WorkspaceConn, desired *schema.Set) (toAttach, toDetach []string) {
+	for _, handle := range desired.List() {
+		if _, ok := attached[handle.(string)]; !ok {
+			toAttach = append(toAttach, handle.(string))
+		}
+	}
+	for handle := range attached {
+		if !desired.Contains(handle) {
+			toDetach = append(toDetach, handle)
+		}
+	}
+	sort.Strings(toAttach)
+	sort.Strings(toDetach)
+	return
+}
@@ -0,0 +1,79 @@
+package steampipecloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func TestAccWorkspaceConnections_Basic(t *testing.T) {
+	resourceName := "steampipecloud_workspace_connections.test"
+	workspaceHandle := "workspace" + randomString(6)
+	connHandle := "aws_" + randomString(4)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceConnectionsConfig(workspaceHandle, connHandle),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTestWorkspaceExists(workspaceHandle),
+					testAccCheckTestConnectionExists(connHandle),
+					resource.TestCheckResourceAttr(resourceName, "workspace_handle", workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "connection_handles.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "connection_handles.*", connHandle),
+					resource.TestCheckTypeSetElemAttr(resourceName, "connection_handles.*", connHandle+"_2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestDiffWorkspaceConnections(t *testing.T) {
+	attached := workspaceConnectionsByHandle([]steampipe.WorkspaceConn{
+		{Connection: &steampipe.Connection{Handle: "aws_a"}},
+		{Connection: &steampipe.Connection{Handle: "aws_b"}},
+		{Connection: &steampipe.Connection{Handle: "all_aws", Type: types.String("aggregator")}},
+	})
+	require.Len(t, attached, 2)
+
+	toAttach, toDetach := diffWorkspaceConnections(attached, schema.NewSet(schema.HashString, []interface{}{"aws_b", "aws_c", "aws_d"}))
+	require.Equal(t, []string{"aws_c", "aws_d"}, toAttach)
+	require.Equal(t, []string{"aws_a"}, toDetach)
+}
+
+func testAccWorkspaceConnectionsConfig(workspace string, conn string) string {
+	return fmt.Sprintf(`
+provider "steampipecloud" {}
+
+resource "steampipecloud_workspace" "test" {
+  handle = "%[1]s"
+}
+
+resource "steampipecloud_connection" "test" {
+	for_each = toset(["%[2]s", "%[2]s_2"])
+	handle   = each.key
+	plugin   = "aws"
+	config = jsonencode({
+		regions    = ["us-east-1"]
+		access_key = "redacted"
+		secret_key = "redacted"
+	})
+}
+
+resource "steampipecloud_workspace_connections" "test" {
+  workspace_handle   = steampipecloud_workspace.test.handle
+  connection_handles = [for conn in steampipecloud_connection.test : conn.handle]
+}`, workspace, conn)
+}
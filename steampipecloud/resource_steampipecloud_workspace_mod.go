@@ -226,6 +226,9 @@ func resourceWorkspaceModRead(ctx context.Context, d *schema.ResourceData, meta
 
 	// Error check
 	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace mod (%s) not found in workspace (%s)", modAlias, workspaceHandle))
+		}
 		return diag.Errorf("error getting workspace mod: %v", decodeResponse(r))
 	}
 	log.Printf("\n[DEBUG] Mod: %s received for Workspace: %s", *resp.Path, workspaceHandle)
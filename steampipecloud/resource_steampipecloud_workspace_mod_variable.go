@@ -237,6 +237,9 @@ func resourceWorkspaceModVariableRead(ctx context.Context, d *schema.ResourceDat
 
 	// Error check
 	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace mod variable (%s) not found for mod (%s) in workspace (%s)", variableName, modAlias, workspaceHandle))
+		}
 		return diag.Errorf("error getting workspace mod variable : %v", decodeResponse(r))
 	}
 	log.Printf("\n[DEBUG] Varible: %s received for Mod: %s in Workspace: %s", variableName, modAlias, workspaceHandle)
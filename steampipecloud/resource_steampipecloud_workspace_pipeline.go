@@ -2,28 +2,39 @@ package steampipecloud
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
 	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
 func resourceWorkspacePipeline() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext: resourceWorkspacePipelineCreate,
 		ReadContext:   resourceWorkspacePipelineRead,
 		UpdateContext: resourceWorkspacePipelineUpdate,
 		DeleteContext: resourceWorkspacePipelineDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customdiff.Sequence(resourceWorkspacePipelineCustomizeDiff, resourceWorkspacePipelineArgsCustomizeDiff),
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validatePipelineScheduleRaw,
+			validatePipelineArgsRaw,
+		},
 		Schema: map[string]*schema.Schema{
 			"workspace_pipeline_id": {
 				Type:     schema.TypeString,
@@ -41,16 +52,26 @@ func resourceWorkspacePipeline() *schema.Resource {
 			},
 			"frequency": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"frequency", "schedule"},
 				ValidateFunc: validation.StringIsJSON,
 			},
-			"pipeline": {
+			"schedule": pipelineScheduleSchema(),
+			"next_run_at": {
 				Type:     schema.TypeString,
-				Required: true,
+				Computed: true,
+			},
+			"pipeline": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validatePipelineName,
 			},
 			"args": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: append([]string{"args"}, pipelineArgsBlockNames()...),
 				ValidateFunc: validation.StringIsJSON,
 			},
 			"tags": {
@@ -87,6 +108,25 @@ func resourceWorkspacePipeline() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"run_on_apply": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"organization": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -99,6 +139,10 @@ func resourceWorkspacePipeline() *schema.Resource {
 			},
 		},
 	}
+	for name, blockSchema := range pipelineArgsBlockSchemas() {
+		resource.Schema[name] = blockSchema
+	}
+	return resource
 }
 
 func resourceWorkspacePipelineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -113,14 +157,13 @@ func resourceWorkspacePipelineCreate(ctx context.Context, d *schema.ResourceData
 	workspaceHandle := d.Get("workspace").(string)
 	title := d.Get("title").(string)
 	pipeline := d.Get("pipeline").(string)
-	var frequency steampipe.PipelineFrequency
-	err = json.Unmarshal([]byte(d.Get("frequency").(string)), &frequency)
+	frequency, err := expandPipelineFrequency(d)
 	if err != nil {
-		return diag.Errorf("error parsing frequency for workspace pipeline : %v", d.Get("frequency").(string))
+		return diag.FromErr(err)
 	}
-	args, err := JSONStringToInterface(d.Get("args").(string))
+	args, err := expandPipelineArgs(d)
 	if err != nil {
-		return diag.Errorf("error parsing args for workspace pipeline : %v", d.Get("args").(string))
+		return diag.FromErr(err)
 	}
 	tags, err := JSONStringToInterface(d.Get("tags").(string))
 	if err != nil {
@@ -151,15 +194,36 @@ func resourceWorkspacePipelineCreate(ctx context.Context, d *schema.ResourceData
 	}
 	log.Printf("\n[DEBUG] Pipeline: %s created for Workspace: %s", resp.Id, workspaceHandle)
 
+	// Pipelines are always created enabled, so a disabled pipeline is disabled once it exists
+	if !d.Get("enabled").(bool) {
+		pipelineId := resp.Id
+		resp, err = setWorkspacePipelineEnabled(ctx, client, userHandle, orgHandle, workspaceHandle, pipelineId, false)
+		if err != nil {
+			if userHandle == "" {
+				d.SetId(fmt.Sprintf("%s/%s/%s", orgHandle, workspaceHandle, pipelineId))
+			} else {
+				d.SetId(fmt.Sprintf("%s/%s", workspaceHandle, pipelineId))
+			}
+			return diag.FromErr(err)
+		}
+	}
+
 	// Set property values
 	d.Set("workspace_pipeline_id", resp.Id)
 	d.Set("workspace_id", resp.WorkspaceId)
 	d.Set("title", resp.Title)
 	d.Set("frequency", FormatJson(resp.Frequency))
+	d.Set("schedule", flattenPipelineFrequency(resp.Frequency))
+	d.Set("next_run_at", resp.NextRunAt)
 	d.Set("pipeline", resp.Pipeline)
 	d.Set("args", FormatJson(resp.Args))
+	if blockName, _ := getPipelineArgsBlock(d); blockName != "" {
+		d.Set(blockName, flattenPipelineArgsBlock(blockName, resp.Args))
+	}
 	d.Set("tags", FormatJson(resp.Tags))
 	d.Set("last_process_id", resp.LastProcessId)
+	d.Set("enabled", resp.DesiredState == pipelineDesiredStateEnabled)
+	d.Set("state", resp.State)
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	if resp.CreatedBy != nil {
@@ -180,6 +244,14 @@ func resourceWorkspacePipelineCreate(ctx context.Context, d *schema.ResourceData
 		d.SetId(fmt.Sprintf("%s/%s", workspaceHandle, resp.Id))
 	}
 
+	if d.Get("run_on_apply").(bool) {
+		process, err := runWorkspacePipeline(ctx, client, userHandle, orgHandle, workspaceHandle, resp.Id, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("last_process_id", process.Id)
+	}
+
 	return diags
 }
 
@@ -225,6 +297,9 @@ func resourceWorkspacePipelineRead(ctx context.Context, d *schema.ResourceData,
 
 	// Error check
 	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace pipeline (%s) not found in workspace (%s)", pipelineId, workspaceHandle))
+		}
 		return diag.Errorf("error getting workspace pipeline: %v", decodeResponse(r))
 	}
 	log.Printf("\n[DEBUG] pipeline: %s received for Workspace: %s", resp.Id, workspaceHandle)
@@ -234,10 +309,17 @@ func resourceWorkspacePipelineRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("workspace_id", resp.WorkspaceId)
 	d.Set("title", resp.Title)
 	d.Set("frequency", FormatJson(resp.Frequency))
+	d.Set("schedule", flattenPipelineFrequency(resp.Frequency))
+	d.Set("next_run_at", resp.NextRunAt)
 	d.Set("pipeline", resp.Pipeline)
 	d.Set("args", FormatJson(resp.Args))
+	if blockName, _ := getPipelineArgsBlock(d); blockName != "" {
+		d.Set(blockName, flattenPipelineArgsBlock(blockName, resp.Args))
+	}
 	d.Set("tags", FormatJson(resp.Tags))
 	d.Set("last_process_id", resp.LastProcessId)
+	d.Set("enabled", resp.DesiredState == pipelineDesiredStateEnabled)
+	d.Set("state", resp.State)
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	if resp.CreatedBy != nil {
@@ -273,14 +355,13 @@ func resourceWorkspacePipelineUpdate(ctx context.Context, d *schema.ResourceData
 	workspaceHandle := d.Get("workspace").(string)
 	pipelineId := d.Get("workspace_pipeline_id").(string)
 	title := d.Get("title").(string)
-	var frequency steampipe.PipelineFrequency
-	err = json.Unmarshal([]byte(d.Get("frequency").(string)), &frequency)
+	frequency, err := expandPipelineFrequency(d)
 	if err != nil {
-		return diag.Errorf("error parsing frequency for workspace pipeline : %v", d.Get("frequency").(string))
+		return diag.FromErr(err)
 	}
-	args, err := JSONStringToInterface(d.Get("args").(string))
+	args, err := expandPipelineArgs(d)
 	if err != nil {
-		return diag.Errorf("error parsing args for workspace pipeline : %v", d.Get("args").(string))
+		return diag.FromErr(err)
 	}
 	tags, err := JSONStringToInterface(d.Get("tags").(string))
 	if err != nil {
@@ -291,7 +372,8 @@ func resourceWorkspacePipelineUpdate(ctx context.Context, d *schema.ResourceData
 	log.Printf("\n[DEBUG] Pipeline Tags: %v", tags)
 
 	// Create request
-	req := steampipe.UpdatePipelineRequest{Title: &title, Frequency: &frequency, Args: args, Tags: tags}
+	desiredState := pipelineDesiredState(d.Get("enabled").(bool))
+	req := steampipe.UpdatePipelineRequest{Title: &title, Frequency: &frequency, Args: args, Tags: tags, DesiredState: &desiredState}
 
 	userHandle := ""
 	isUser, orgHandle := isUserConnection(d)
@@ -316,10 +398,17 @@ func resourceWorkspacePipelineUpdate(ctx context.Context, d *schema.ResourceData
 	d.Set("workspace_id", resp.WorkspaceId)
 	d.Set("title", resp.Title)
 	d.Set("frequency", FormatJson(resp.Frequency))
+	d.Set("schedule", flattenPipelineFrequency(resp.Frequency))
+	d.Set("next_run_at", resp.NextRunAt)
 	d.Set("pipeline", resp.Pipeline)
 	d.Set("args", FormatJson(resp.Args))
+	if blockName, _ := getPipelineArgsBlock(d); blockName != "" {
+		d.Set(blockName, flattenPipelineArgsBlock(blockName, resp.Args))
+	}
 	d.Set("tags", FormatJson(resp.Tags))
 	d.Set("last_process_id", resp.LastProcessId)
+	d.Set("enabled", resp.DesiredState == pipelineDesiredStateEnabled)
+	d.Set("state", resp.State)
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	if resp.CreatedBy != nil {
@@ -340,9 +429,103 @@ func resourceWorkspacePipelineUpdate(ctx context.Context, d *schema.ResourceData
 		d.SetId(fmt.Sprintf("%s/%s", workspaceHandle, resp.Id))
 	}
 
+	// The pipeline is run again when it is switched to run on apply or its triggers change
+	if d.Get("run_on_apply").(bool) && (d.HasChange("run_on_apply") || d.HasChange("triggers")) {
+		process, err := runWorkspacePipeline(ctx, client, userHandle, orgHandle, workspaceHandle, resp.Id, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			// Keep the previous triggers so that the run is attempted again on the next apply
+			oldTriggers, _ := d.GetChange("triggers")
+			oldRunOnApply, _ := d.GetChange("run_on_apply")
+			d.Set("triggers", oldTriggers)
+			d.Set("run_on_apply", oldRunOnApply)
+			return diag.FromErr(err)
+		}
+		d.Set("last_process_id", process.Id)
+	}
+
 	return diags
 }
 
+// expandPipelineArgs returns the pipeline args from the typed args block if one is set, otherwise from the JSON args
+func expandPipelineArgs(d *schema.ResourceData) (interface{}, error) {
+	if _, block := getPipelineArgsBlock(d); block != nil {
+		return expandPipelineArgsBlock(block)
+	}
+	args, err := JSONStringToInterface(d.Get("args").(string))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing args for workspace pipeline : %v", d.Get("args").(string))
+	}
+	return args, nil
+}
+
+const (
+	pipelineDesiredStateEnabled  = "enabled"
+	pipelineDesiredStateDisabled = "disabled"
+)
+
+func pipelineDesiredState(enabled bool) string {
+	if enabled {
+		return pipelineDesiredStateEnabled
+	}
+	return pipelineDesiredStateDisabled
+}
+
+// setWorkspacePipelineEnabled enables or disables the schedule of a pipeline
+func setWorkspacePipelineEnabled(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, pipelineId string, enabled bool) (steampipe.Pipeline, error) {
+	var resp steampipe.Pipeline
+	var r *http.Response
+	var err error
+
+	desiredState := pipelineDesiredState(enabled)
+	req := steampipe.UpdatePipelineRequest{DesiredState: &desiredState}
+	if orgHandle == "" {
+		resp, r, err = client.APIClient.UserWorkspacePipelines.Update(ctx, userHandle, workspaceHandle, pipelineId).Request(req).Execute()
+	} else {
+		resp, r, err = client.APIClient.OrgWorkspacePipelines.Update(ctx, orgHandle, workspaceHandle, pipelineId).Request(req).Execute()
+	}
+	if err != nil {
+		return resp, fmt.Errorf("error setting desired state of workspace pipeline %s to %s: %v", pipelineId, desiredState, decodeResponse(r))
+	}
+	return resp, nil
+}
+
+// startWorkspacePipeline runs the pipeline and returns the ID of the process of the run
+func startWorkspacePipeline(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, pipelineId string) (string, error) {
+	var resp steampipe.PipelineCommandResponse
+	var r *http.Response
+	var err error
+
+	req := steampipe.PipelineCommandRequest{Command: "run"}
+	if orgHandle == "" {
+		resp, r, err = client.APIClient.UserWorkspacePipelines.Command(ctx, userHandle, workspaceHandle, pipelineId).Request(req).Execute()
+	} else {
+		resp, r, err = client.APIClient.OrgWorkspacePipelines.Command(ctx, orgHandle, workspaceHandle, pipelineId).Request(req).Execute()
+	}
+	if err != nil {
+		return "", fmt.Errorf("error running workspace pipeline %s: %v", pipelineId, decodeResponse(r))
+	}
+	log.Printf("\n[DEBUG] Pipeline: %s started process: %s", pipelineId, resp.ProcessId)
+	return resp.ProcessId, nil
+}
+
+// runWorkspacePipeline runs the pipeline and waits for the process of the run to finish. An error is returned
+// if the process fails, is cancelled or does not finish within the timeout.
+func runWorkspacePipeline(ctx context.Context, client *SteampipeClient, userHandle, orgHandle, workspaceHandle, pipelineId string, timeout time.Duration) (steampipe.SpProcess, error) {
+	processId, err := startWorkspacePipeline(ctx, client, userHandle, orgHandle, workspaceHandle, pipelineId)
+	if err != nil {
+		return steampipe.SpProcess{}, err
+	}
+
+	process, err := waitForProcess(ctx, client, userHandle, orgHandle, workspaceHandle, processId, timeout)
+	if err != nil {
+		return process, fmt.Errorf("error waiting for run of workspace pipeline %s: %v", pipelineId, err)
+	}
+	if state := types.SafeString(process.State); state != processStateCompleted {
+		return process, fmt.Errorf("run of workspace pipeline %s %s, see process %s for details", pipelineId, state, process.Id)
+	}
+	return process, nil
+}
+
 func resourceWorkspacePipelineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*SteampipeClient)
 
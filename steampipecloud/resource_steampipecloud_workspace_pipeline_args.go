@@ -0,0 +1,304 @@
+package steampipecloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	pipelineSnapshotDashboard = "pipeline.snapshot_dashboard"
+	pipelineSnapshotQuery     = "pipeline.snapshot_query"
+)
+
+// The pipelines which can be created in a workspace, with the name of the typed args block for each
+var pipelineArgsBlocks = map[string]string{
+	pipelineSnapshotDashboard: "snapshot_dashboard",
+	pipelineSnapshotQuery:     "snapshot_query",
+}
+
+const (
+	snapshotVisibilityWorkspace      = "workspace"
+	snapshotVisibilityAnyoneWithLink = "anyone_with_link"
+)
+
+var snapshotVisibilities = []string{snapshotVisibilityWorkspace, snapshotVisibilityAnyoneWithLink}
+
+// Dashboards and benchmarks are referenced by their fully qualified name, e.g. aws_compliance.benchmark.cis_v140
+var pipelineDashboardResourceRegex = regexp.MustCompile(`^[a-z0-9_]+\.(dashboard|benchmark)\.[a-z0-9_]+$`)
+
+// Dashboard inputs are named either input.<name> or <name>
+var pipelineDashboardInputRegex = regexp.MustCompile(`^(input\.)?[a-z0-9_]+$`)
+
+func pipelineNames() []string {
+	return sortedKeys(pipelineArgsBlocks)
+}
+
+// validatePipelineName warns about pipelines which the provider does not know, since Steampipe Cloud may support
+// pipelines which were added after this provider was released
+func validatePipelineName(value interface{}, path cty.Path) diag.Diagnostics {
+	pipeline := value.(string)
+	if _, ok := pipelineArgsBlocks[pipeline]; ok {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Warning,
+		Summary:       "Unrecognized pipeline",
+		Detail:        fmt.Sprintf("The %s pipeline is not known to this provider, so its args are not validated. Known pipelines are %s.", pipeline, strings.Join(pipelineNames(), ", ")),
+		AttributePath: path,
+	}}
+}
+
+func pipelineArgsBlockNames() []string {
+	var names []string
+	for _, pipeline := range pipelineNames() {
+		names = append(names, pipelineArgsBlocks[pipeline])
+	}
+	return names
+}
+
+func pipelineArgsBlockSchemas() map[string]*schema.Schema {
+	exactlyOneOf := append([]string{"args"}, pipelineArgsBlockNames()...)
+	snapshotSchema := func() map[string]*schema.Schema {
+		return map[string]*schema.Schema{
+			"snapshot_tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(snapshotVisibilities, false),
+			},
+			"notifications": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+		}
+	}
+
+	dashboardSchema := snapshotSchema()
+	dashboardSchema["resource"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.StringMatch(pipelineDashboardResourceRegex, "Resource must be the fully qualified name of a dashboard or benchmark, e.g. aws_compliance.benchmark.cis_v140."),
+	}
+	dashboardSchema["inputs"] = &schema.Schema{
+		Type:             schema.TypeMap,
+		Optional:         true,
+		Elem:             &schema.Schema{Type: schema.TypeString},
+		ValidateDiagFunc: validation.MapKeyMatch(pipelineDashboardInputRegex, "Input names must be of the form input.<name> or <name>, and may only contain lowercase alphanumeric characters or underscores."),
+	}
+	dashboardSchema["variables"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	querySchema := snapshotSchema()
+	querySchema["query"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.StringIsNotWhiteSpace,
+	}
+
+	return map[string]*schema.Schema{
+		"snapshot_dashboard": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			ExactlyOneOf: exactlyOneOf,
+			Elem:         &schema.Resource{Schema: dashboardSchema},
+		},
+		"snapshot_query": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			ExactlyOneOf: exactlyOneOf,
+			Elem:         &schema.Resource{Schema: querySchema},
+		},
+	}
+}
+
+// getPipelineArgsBlock returns the name and contents of the typed args block which is set, if any
+func getPipelineArgsBlock(d interface{ Get(string) interface{} }) (string, map[string]interface{}) {
+	for _, name := range pipelineArgsBlockNames() {
+		if blocks, ok := d.Get(name).([]interface{}); ok && len(blocks) > 0 {
+			block, _ := blocks[0].(map[string]interface{})
+			if block == nil {
+				block = map[string]interface{}{}
+			}
+			return name, block
+		}
+	}
+	return "", nil
+}
+
+// expandPipelineArgsBlock converts a typed args block into the pipeline args sent to the API.
+// Unset arguments are left out so the pipeline defaults apply.
+func expandPipelineArgsBlock(block map[string]interface{}) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	for _, key := range sortedKeys(block) {
+		switch value := block[key].(type) {
+		case string:
+			if value == "" {
+				continue
+			}
+			if key == "notifications" {
+				notifications, err := JSONStringToInterface(value)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing notifications for workspace pipeline : %v", value)
+				}
+				args[key] = notifications
+				continue
+			}
+			args[key] = value
+		case map[string]interface{}:
+			if len(value) > 0 {
+				args[key] = value
+			}
+		}
+	}
+	return args, nil
+}
+
+// flattenPipelineArgsBlock converts the pipeline args returned by the API into the typed args block, keeping
+// only the arguments that the block knows about
+func flattenPipelineArgsBlock(blockName string, args interface{}) []map[string]interface{} {
+	argsMap, _ := args.(map[string]interface{})
+	block := map[string]interface{}{}
+	for key, field := range pipelineArgsBlockSchemas()[blockName].Elem.(*schema.Resource).Schema {
+		value, ok := argsMap[key]
+		if !ok || value == nil {
+			continue
+		}
+		switch field.Type {
+		case schema.TypeMap:
+			values := map[string]interface{}{}
+			if items, ok := value.(map[string]interface{}); ok {
+				for k, v := range items {
+					if s, ok := v.(string); ok {
+						values[k] = s
+					} else {
+						values[k] = FormatJson(v)
+					}
+				}
+			}
+			block[key] = values
+		case schema.TypeString:
+			if s, ok := value.(string); ok {
+				block[key] = s
+			} else {
+				block[key] = FormatJson(value)
+			}
+		}
+	}
+	return []map[string]interface{}{block}
+}
+
+// validatePipelineArgsRaw checks during plan that the typed args block matches the pipeline, and that JSON args
+// include the arguments required by the pipeline
+func validatePipelineArgsRaw(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if !req.RawConfig.IsKnown() || req.RawConfig.IsNull() {
+		return
+	}
+	pipelineValue := req.RawConfig.GetAttr("pipeline")
+	if !pipelineValue.IsKnown() || pipelineValue.IsNull() {
+		return
+	}
+	pipeline := pipelineValue.AsString()
+	// unknown pipelines are warned about by the attribute validation, and can only be given JSON args
+	expectedBlock, known := pipelineArgsBlocks[pipeline]
+
+	for _, name := range pipelineArgsBlockNames() {
+		block := req.RawConfig.GetAttr(name)
+		if name == expectedBlock || !block.IsKnown() || block.IsNull() || block.LengthInt() == 0 {
+			continue
+		}
+		detail := fmt.Sprintf("The %s block cannot be used with the %s pipeline, use the %s block instead.", name, pipeline, expectedBlock)
+		if !known {
+			detail = fmt.Sprintf("The %s block cannot be used with the %s pipeline, use args instead.", name, pipeline)
+		}
+		resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid pipeline args block",
+			Detail:        detail,
+			AttributePath: cty.GetAttrPath(name),
+		})
+	}
+	if !known {
+		return
+	}
+
+	args := req.RawConfig.GetAttr("args")
+	if !args.IsKnown() || args.IsNull() {
+		return
+	}
+	var argsMap map[string]interface{}
+	if err := json.Unmarshal([]byte(args.AsString()), &argsMap); err != nil {
+		// invalid JSON is reported by the attribute validation
+		return
+	}
+	if err := validatePipelineArgs(pipeline, argsMap); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid pipeline args",
+			Detail:        err.Error(),
+			AttributePath: cty.GetAttrPath("args"),
+		})
+	}
+}
+
+// validatePipelineArgs checks the JSON args of a pipeline against the arguments of its typed args block
+func validatePipelineArgs(pipeline string, args map[string]interface{}) error {
+	switch pipeline {
+	case pipelineSnapshotDashboard:
+		resource, ok := args["resource"].(string)
+		if !ok {
+			return fmt.Errorf("the %s pipeline requires a resource argument naming the dashboard or benchmark to snapshot", pipeline)
+		}
+		if !pipelineDashboardResourceRegex.MatchString(resource) {
+			return fmt.Errorf("resource %q must be the fully qualified name of a dashboard or benchmark, e.g. aws_compliance.benchmark.cis_v140", resource)
+		}
+		if inputs, ok := args["inputs"].(map[string]interface{}); ok {
+			for _, name := range sortedKeys(inputs) {
+				if !pipelineDashboardInputRegex.MatchString(name) {
+					return fmt.Errorf("input %q must be of the form input.<name> or <name>", name)
+				}
+			}
+		}
+	case pipelineSnapshotQuery:
+		if query, ok := args["query"].(string); !ok || query == "" {
+			return fmt.Errorf("the %s pipeline requires a query argument", pipeline)
+		}
+	}
+	if visibility, ok := args["visibility"]; ok {
+		for _, valid := range snapshotVisibilities {
+			if visibility == valid {
+				return nil
+			}
+		}
+		return fmt.Errorf("visibility %v must be one of %v", visibility, snapshotVisibilities)
+	}
+	return nil
+}
+
+// resourceWorkspacePipelineArgsCustomizeDiff plans the JSON args as unknown when the typed args block changes
+func resourceWorkspacePipelineArgsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if blockName, _ := getPipelineArgsBlock(d); blockName != "" && d.HasChange(blockName) {
+		return d.SetNewComputed("args")
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package steampipecloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+const (
+	pipelineScheduleTypeInterval = "interval"
+	pipelineScheduleTypeCron     = "cron"
+	pipelineScheduleTypeManual   = "manual"
+)
+
+var pipelineScheduleTypes = []string{pipelineScheduleTypeInterval, pipelineScheduleTypeCron, pipelineScheduleTypeManual}
+
+// The intervals at which Steampipe Cloud can run a pipeline
+var pipelineScheduleIntervals = []string{"hourly", "daily", "weekly", "monthly"}
+
+func pipelineScheduleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		Computed:     true,
+		MaxItems:     1,
+		ExactlyOneOf: []string{"frequency", "schedule"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(pipelineScheduleTypes, false),
+				},
+				"schedule": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// validatePipelineScheduleRaw validates the schedule of the pipeline during plan, whether it is set in the
+// schedule block or in the JSON frequency
+func validatePipelineScheduleRaw(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if !req.RawConfig.IsKnown() || req.RawConfig.IsNull() {
+		return
+	}
+
+	if blocks := req.RawConfig.GetAttr("schedule"); blocks.IsKnown() && !blocks.IsNull() && blocks.LengthInt() == 1 {
+		block := blocks.Index(cty.NumberIntVal(0))
+		if !block.IsKnown() || block.IsNull() {
+			return
+		}
+		scheduleType, schedule := block.GetAttr("type"), block.GetAttr("schedule")
+		if !scheduleType.IsKnown() || scheduleType.IsNull() || !schedule.IsKnown() {
+			return
+		}
+		var value string
+		if !schedule.IsNull() {
+			value = schedule.AsString()
+		}
+		if err := validatePipelineSchedule(scheduleType.AsString(), value); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid pipeline schedule",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("schedule").IndexInt(0).GetAttr("schedule"),
+			})
+		}
+	}
+
+	if frequency := req.RawConfig.GetAttr("frequency"); frequency.IsKnown() && !frequency.IsNull() {
+		var value steampipe.PipelineFrequency
+		if err := json.Unmarshal([]byte(frequency.AsString()), &value); err != nil {
+			// invalid JSON is reported by the attribute validation
+			return
+		}
+		if err := validatePipelineSchedule(value.Type, value.GetSchedule()); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid pipeline frequency",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("frequency"),
+			})
+		}
+	}
+}
+
+// validatePipelineSchedule checks that the schedule is valid for the schedule type
+func validatePipelineSchedule(scheduleType, schedule string) error {
+	switch scheduleType {
+	case pipelineScheduleTypeInterval:
+		for _, interval := range pipelineScheduleIntervals {
+			if schedule == interval {
+				return nil
+			}
+		}
+		return fmt.Errorf("interval schedule %q must be one of %s", schedule, strings.Join(pipelineScheduleIntervals, ", "))
+	case pipelineScheduleTypeCron:
+		if err := validateCronExpression(schedule); err != nil {
+			return fmt.Errorf("invalid cron schedule %q: %v", schedule, err)
+		}
+		return nil
+	case pipelineScheduleTypeManual:
+		if schedule != "" {
+			return fmt.Errorf("a manual pipeline cannot have a schedule")
+		}
+		return nil
+	}
+	return fmt.Errorf("schedule type %q must be one of %s", scheduleType, strings.Join(pipelineScheduleTypes, ", "))
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is also accepted for Sunday
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// validateCronExpression checks a standard five field cron expression, e.g. "0 9 * * MON-FRI"
+func validateCronExpression(expression string) error {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields (minute, hour, day of month, month, day of week), got %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		if err := cronFields[i].validate(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f cronField) validate(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		valueRange, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q in %s field", step, f.name)
+			}
+		}
+		if valueRange == "*" {
+			continue
+		}
+		start, end, isRange := strings.Cut(valueRange, "-")
+		from, err := f.parseValue(start)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		to, err := f.parseValue(end)
+		if err != nil {
+			return err
+		}
+		if from > to {
+			return fmt.Errorf("invalid range %q in %s field", valueRange, f.name)
+		}
+	}
+	return nil
+}
+
+func (f cronField) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			return i + f.min, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, expected %d-%d", value, f.name, f.min, f.max)
+	}
+	return n, nil
+}
+
+// expandPipelineFrequency returns the frequency of the pipeline from the schedule block if it is configured,
+// otherwise from the JSON frequency
+func expandPipelineFrequency(d *schema.ResourceData) (steampipe.PipelineFrequency, error) {
+	var frequency steampipe.PipelineFrequency
+	if config := d.GetRawConfig(); !config.IsNull() && !config.GetAttr("schedule").IsNull() && config.GetAttr("schedule").LengthInt() > 0 {
+		frequency.Type = d.Get("schedule.0.type").(string)
+		if schedule := d.Get("schedule.0.schedule").(string); schedule != "" {
+			frequency.SetSchedule(schedule)
+		}
+		return frequency, nil
+	}
+	if err := json.Unmarshal([]byte(d.Get("frequency").(string)), &frequency); err != nil {
+		return frequency, fmt.Errorf("error parsing frequency for workspace pipeline : %v", d.Get("frequency").(string))
+	}
+	return frequency, nil
+}
+
+func flattenPipelineFrequency(frequency steampipe.PipelineFrequency) []map[string]interface{} {
+	return []map[string]interface{}{{
+		"type":     frequency.Type,
+		"schedule": frequency.GetSchedule(),
+	}}
+}
+
+// resourceWorkspacePipelineCustomizeDiff plans the frequency, schedule and next run time as unknown when the
+// schedule changes, since only one of frequency or schedule is configured and the other follows it
+func resourceWorkspacePipelineCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return nil
+	}
+	scheduleConfigured := !config.GetAttr("schedule").IsNull() && (!config.GetAttr("schedule").IsKnown() || config.GetAttr("schedule").LengthInt() > 0)
+	if scheduleConfigured && d.HasChange("schedule") {
+		if err := d.SetNewComputed("frequency"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("next_run_at")
+	}
+	if !scheduleConfigured && d.HasChange("frequency") {
+		if err := d.SetNewComputed("schedule"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("next_run_at")
+	}
+	return nil
+}
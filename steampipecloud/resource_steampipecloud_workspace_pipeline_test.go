@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"reflect"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
@@ -17,6 +20,7 @@ import (
 func TestAccUserWorkspacePipeline_Basic(t *testing.T) {
 	resourceName := "steampipecloud_workspace_pipeline.pipeline_1"
 	processDataSourceName := "data.steampipecloud_process.process_run"
+	processesDataSourceName := "data.steampipecloud_processes.pipeline_runs"
 	workspaceHandle := "workspace" + randomString(3)
 	title := "Daily CIS Job"
 	pipeline := "pipeline.snapshot_dashboard"
@@ -106,12 +110,407 @@ func TestAccUserWorkspacePipeline_Basic(t *testing.T) {
 					TestJSONFieldEqual(t, resourceName, "tags", tags),
 					resource.TestMatchResourceAttr(processDataSourceName, "process_id", regexp.MustCompile(`^p_[0-9a-v]{20}`)),
 					resource.TestCheckResourceAttr(processDataSourceName, "type", "pipeline.command.run"),
+					resource.TestMatchResourceAttr(processDataSourceName, "state", regexp.MustCompile(`^(completed|failed|cancell?ed)$`)),
+					resource.TestCheckResourceAttrSet(processDataSourceName, "duration"),
+					resource.TestCheckResourceAttr(processesDataSourceName, "processes.#", "1"),
+					resource.TestCheckResourceAttrPair(processesDataSourceName, "processes.0.process_id", processDataSourceName, "process_id"),
+					resource.TestCheckResourceAttr(processesDataSourceName, "processes.0.type", "pipeline.command.run"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccUserWorkspacePipeline_Schedule(t *testing.T) {
+	resourceName := "steampipecloud_workspace_pipeline.pipeline_1"
+	workspaceHandle := "workspace" + randomString(3)
+	mod := "github.com/turbot/steampipe-mod-aws-compliance"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspacePipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserWorkspacePipelineScheduleConfig(workspaceHandle, mod, "interval", "fortnightly"),
+				ExpectError: regexp.MustCompile(`interval schedule "fortnightly" must be one of`),
+			},
+			{
+				Config:      testAccUserWorkspacePipelineScheduleConfig(workspaceHandle, mod, "cron", "0 25 * * *"),
+				ExpectError: regexp.MustCompile(`invalid value "25" in hour field`),
+			},
+			{
+				Config: testAccUserWorkspacePipelineScheduleConfig(workspaceHandle, mod, "interval", "daily"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.type", "interval"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.schedule", "daily"),
+					TestJSONFieldEqual(t, resourceName, "frequency", `{"type": "interval", "schedule": "daily"}`),
+					resource.TestCheckResourceAttrSet(resourceName, "next_run_at"),
+				),
+			},
+			{
+				Config: testAccUserWorkspacePipelineScheduleConfig(workspaceHandle, mod, "cron", "0 9 * * MON-FRI"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.type", "cron"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.schedule", "0 9 * * MON-FRI"),
+					TestJSONFieldEqual(t, resourceName, "frequency", `{"type": "cron", "schedule": "0 9 * * MON-FRI"}`),
+					resource.TestCheckResourceAttrSet(resourceName, "next_run_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidatePipelineSchedule(t *testing.T) {
+	tests := []struct {
+		scheduleType string
+		schedule     string
+		valid        bool
+	}{
+		{"interval", "daily", true},
+		{"interval", "hourly", true},
+		{"interval", "fortnightly", false},
+		{"interval", "", false},
+		{"manual", "", true},
+		{"manual", "daily", false},
+		{"cron", "*/15 * * * *", true},
+		{"cron", "0 9 * * MON-FRI", true},
+		{"cron", "0 0 1,15 jan-jun 0", true},
+		{"cron", "30 2 * * 7", true},
+		{"cron", "0 9 * *", false},
+		{"cron", "0 24 * * *", false},
+		{"cron", "0 0 0 * *", false},
+		{"cron", "*/0 * * * *", false},
+		{"cron", "0 0 * 12-1 *", false},
+		{"cron", "0 0 * * funday", false},
+		{"weekly", "", false},
+	}
+	for _, test := range tests {
+		err := validatePipelineSchedule(test.scheduleType, test.schedule)
+		if test.valid && err != nil {
+			t.Errorf("validatePipelineSchedule(%q, %q) returned error: %v", test.scheduleType, test.schedule, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("validatePipelineSchedule(%q, %q) expected an error", test.scheduleType, test.schedule)
+		}
+	}
+}
+
+func testAccUserWorkspacePipelineScheduleConfig(workspaceHandle, mod, scheduleType, schedule string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_mod" "aws_compliance" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		path = "%s"
+	}
+
+	resource "steampipecloud_workspace_pipeline" "pipeline_1" {
+		workspace = steampipecloud_workspace.test_workspace.handle
+		title     = "Daily CIS Job"
+		pipeline  = "pipeline.snapshot_dashboard"
+		schedule {
+			type     = "%s"
+			schedule = "%s"
+		}
+		args = jsonencode({
+			"resource": "aws_compliance.benchmark.cis_v140",
+			"inputs": {}
+		})
+
+		depends_on = [steampipecloud_workspace_mod.aws_compliance]
+	}`, workspaceHandle, mod, scheduleType, schedule)
+}
+
+func TestAccUserWorkspacePipeline_Args(t *testing.T) {
+	resourceName := "steampipecloud_workspace_pipeline.pipeline_1"
+	workspaceHandle := "workspace" + randomString(3)
+	mod := "github.com/turbot/steampipe-mod-aws-compliance"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspacePipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserWorkspacePipelineArgsConfig(workspaceHandle, mod, "aws_compliance.cis_v140", "workspace"),
+				ExpectError: regexp.MustCompile(`Resource must be the fully qualified name of a dashboard or benchmark`),
+			},
+			{
+				Config: testAccUserWorkspacePipelineArgsConfig(workspaceHandle, mod, "aws_compliance.benchmark.cis_v140", "workspace"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "snapshot_dashboard.0.resource", "aws_compliance.benchmark.cis_v140"),
+					resource.TestCheckResourceAttr(resourceName, "snapshot_dashboard.0.visibility", "workspace"),
+					resource.TestCheckResourceAttr(resourceName, "snapshot_dashboard.0.snapshot_tags.series", "daily_cis"),
+					TestJSONFieldEqual(t, resourceName, "args", `{"resource": "aws_compliance.benchmark.cis_v140", "snapshot_tags": {"series": "daily_cis"}, "visibility": "workspace"}`),
+				),
+			},
+			{
+				Config: testAccUserWorkspacePipelineArgsConfig(workspaceHandle, mod, "aws_compliance.benchmark.cis_v150", "anyone_with_link"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "snapshot_dashboard.0.resource", "aws_compliance.benchmark.cis_v150"),
+					resource.TestCheckResourceAttr(resourceName, "snapshot_dashboard.0.visibility", "anyone_with_link"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserWorkspacePipeline_RunOnApply(t *testing.T) {
+	resourceName := "steampipecloud_workspace_pipeline.pipeline_1"
+	workspaceHandle := "workspace" + randomString(3)
+	mod := "github.com/turbot/steampipe-mod-aws-compliance"
+	var processId string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspacePipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserWorkspacePipelineRunOnApplyConfig(workspaceHandle, mod, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestMatchResourceAttr(resourceName, "last_process_id", regexp.MustCompile(`^p_[0-9a-v]{20}`)),
+					testAccCapturePipelineLastProcessId(resourceName, &processId),
+				),
+			},
+			{
+				Config: testAccUserWorkspacePipelineRunOnApplyConfig(workspaceHandle, mod, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "last_process_id", regexp.MustCompile(`^p_[0-9a-v]{20}`)),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.Attributes["last_process_id"] == processId {
+							return fmt.Errorf("pipeline was not run again when its triggers changed")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"updated_at", "args", "frequency", "tags", "run_on_apply", "triggers", "snapshot_dashboard", "schedule"},
+			},
+		},
+	})
+}
+
+func TestAccUserWorkspacePipeline_Enabled(t *testing.T) {
+	resourceName := "steampipecloud_workspace_pipeline.pipeline_1"
+	workspaceHandle := "workspace" + randomString(3)
+	mod := "github.com/turbot/steampipe-mod-aws-compliance"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspacePipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserWorkspacePipelineEnabledConfig(workspaceHandle, mod, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"updated_at", "args", "frequency", "tags", "snapshot_dashboard", "schedule"},
+			},
+			{
+				Config: testAccUserWorkspacePipelineEnabledConfig(workspaceHandle, mod, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspacePipelineExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidatePipelineArgs(t *testing.T) {
+	tests := []struct {
+		pipeline string
+		args     map[string]interface{}
+		valid    bool
+	}{
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "aws_compliance.benchmark.cis_v140"}, true},
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "aws_insights.dashboard.aws_account_report", "inputs": map[string]interface{}{"input.region": "us-east-1"}}, true},
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "aws_compliance.benchmark.cis_v140", "visibility": "anyone_with_link"}, true},
+		{pipelineSnapshotDashboard, map[string]interface{}{}, false},
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "cis_v140"}, false},
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "aws_compliance.benchmark.cis_v140", "inputs": map[string]interface{}{"Region": "us-east-1"}}, false},
+		{pipelineSnapshotDashboard, map[string]interface{}{"resource": "aws_compliance.benchmark.cis_v140", "visibility": "public"}, false},
+		{pipelineSnapshotQuery, map[string]interface{}{"query": "select 1"}, true},
+		{pipelineSnapshotQuery, map[string]interface{}{"query": ""}, false},
+	}
+	for _, test := range tests {
+		err := validatePipelineArgs(test.pipeline, test.args)
+		if test.valid && err != nil {
+			t.Errorf("validatePipelineArgs(%q, %v) returned error: %v", test.pipeline, test.args, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("validatePipelineArgs(%q, %v) expected an error", test.pipeline, test.args)
+		}
+	}
+}
+
+func TestValidatePipelineName(t *testing.T) {
+	for _, pipeline := range pipelineNames() {
+		if diags := validatePipelineName(pipeline, cty.GetAttrPath("pipeline")); len(diags) != 0 {
+			t.Errorf("validatePipelineName(%q) returned %v", pipeline, diags)
+		}
+	}
+	// pipelines which the provider does not know are allowed with a warning
+	diags := validatePipelineName("pipeline.save_snapshot", cty.GetAttrPath("pipeline"))
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("validatePipelineName(%q) returned %v, expected a warning", "pipeline.save_snapshot", diags)
+	}
+}
+
+func TestExpandPipelineArgsBlock(t *testing.T) {
+	block := map[string]interface{}{
+		"resource":      "aws_compliance.benchmark.cis_v140",
+		"inputs":        map[string]interface{}{},
+		"snapshot_tags": map[string]interface{}{"series": "daily_cis"},
+		"visibility":    "",
+		"notifications": `{"slack": {"channel": "#compliance"}}`,
+	}
+	args, err := expandPipelineArgsBlock(block)
+	if err != nil {
+		t.Fatalf("expandPipelineArgsBlock returned error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"resource":      "aws_compliance.benchmark.cis_v140",
+		"snapshot_tags": map[string]interface{}{"series": "daily_cis"},
+		"notifications": map[string]interface{}{"slack": map[string]interface{}{"channel": "#compliance"}},
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expandPipelineArgsBlock returned %v, expected %v", args, expected)
+	}
+
+	flattened := flattenPipelineArgsBlock("snapshot_dashboard", map[string]interface{}(args))[0]
+	if flattened["resource"] != "aws_compliance.benchmark.cis_v140" || flattened["notifications"] != `{"slack":{"channel":"#compliance"}}` {
+		t.Errorf("flattenPipelineArgsBlock returned %v", flattened)
+	}
+	if _, ok := flattened["visibility"]; ok {
+		t.Errorf("flattenPipelineArgsBlock set visibility which is not in the args")
+	}
+}
+
+func testAccUserWorkspacePipelineArgsConfig(workspaceHandle, mod, dashboard, visibility string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_mod" "aws_compliance" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		path = "%s"
+	}
+
+	resource "steampipecloud_workspace_pipeline" "pipeline_1" {
+		workspace = steampipecloud_workspace.test_workspace.handle
+		title     = "Daily CIS Job"
+		pipeline  = "pipeline.snapshot_dashboard"
+		schedule {
+			type     = "interval"
+			schedule = "daily"
+		}
+		snapshot_dashboard {
+			resource   = "%s"
+			visibility = "%s"
+			snapshot_tags = {
+				series = "daily_cis"
+			}
+		}
+
+		depends_on = [steampipecloud_workspace_mod.aws_compliance]
+	}`, workspaceHandle, mod, dashboard, visibility)
+}
+
+func testAccCapturePipelineLastProcessId(resourceName string, processId *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		*processId = s.RootModule().Resources[resourceName].Primary.Attributes["last_process_id"]
+		return nil
+	}
+}
+
+func testAccUserWorkspacePipelineRunOnApplyConfig(workspaceHandle, mod, trigger string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_mod" "aws_compliance" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		path = "%s"
+	}
+
+	resource "steampipecloud_workspace_pipeline" "pipeline_1" {
+		workspace    = steampipecloud_workspace.test_workspace.handle
+		title        = "Daily CIS Job"
+		pipeline     = "pipeline.snapshot_dashboard"
+		run_on_apply = true
+		triggers = {
+			run = "%s"
+		}
+		schedule {
+			type = "manual"
+		}
+		snapshot_dashboard {
+			resource = "aws_compliance.benchmark.cis_v140"
+		}
+
+		depends_on = [steampipecloud_workspace_mod.aws_compliance]
+	}`, workspaceHandle, mod, trigger)
+}
+
+func testAccUserWorkspacePipelineEnabledConfig(workspaceHandle, mod string, enabled bool) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_mod" "aws_compliance" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		path = "%s"
+	}
+
+	resource "steampipecloud_workspace_pipeline" "pipeline_1" {
+		workspace = steampipecloud_workspace.test_workspace.handle
+		title     = "Daily CIS Job"
+		pipeline  = "pipeline.snapshot_dashboard"
+		enabled   = %t
+		schedule {
+			type     = "interval"
+			schedule = "daily"
+		}
+		snapshot_dashboard {
+			resource = "aws_compliance.benchmark.cis_v140"
+		}
+
+		depends_on = [steampipecloud_workspace_mod.aws_compliance]
+	}`, workspaceHandle, mod, enabled)
+}
+
 func testAccUserWorkspacePipelineConfig(workspaceHandle, title, pipeline, frequency, args, tags, mod string) string {
 	return fmt.Sprintf(`
 	provider "steampipecloud" {}
@@ -187,8 +586,18 @@ func testAccUserWorkspacePipelineProcessConfig(workspaceHandle, title, pipeline,
 	}
 	
 	data "steampipecloud_process" "process_run" {
-		workspace  = steampipecloud_workspace.test_workspace.handle
-		process_id = steampipecloud_workspace_pipeline.pipeline_1.last_process_id
+		workspace               = steampipecloud_workspace.test_workspace.handle
+		process_id              = steampipecloud_workspace_pipeline.pipeline_1.last_process_id
+		wait_for_terminal_state = true
+		timeout                 = "10m"
+	}
+
+	data "steampipecloud_processes" "pipeline_runs" {
+		workspace   = steampipecloud_workspace.test_workspace.handle
+		pipeline_id = steampipecloud_workspace_pipeline.pipeline_1.workspace_pipeline_id
+		type        = "pipeline.command.run"
+
+		depends_on = [data.steampipecloud_process.process_run]
 	}
 	`, workspaceHandle, mod, title, pipeline, frequency, args, tags)
 }
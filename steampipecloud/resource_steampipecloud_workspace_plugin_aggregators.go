@@ -0,0 +1,409 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+const defaultPluginAggregatorHandlePrefix = "all_"
+
+func resourceWorkspacePluginAggregators() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWorkspacePluginAggregatorsCreate,
+		ReadContext:   resourceWorkspacePluginAggregatorsRead,
+		UpdateContext: resourceWorkspacePluginAggregatorsUpdate,
+		DeleteContext: resourceWorkspacePluginAggregatorsDelete,
+		CustomizeDiff: resourceWorkspacePluginAggregatorsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWorkspacePluginAggregatorsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"workspace_handle": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z0-9]{1,23}$`), "Handle must be between 1 and 23 characters, and may only contain alphanumeric characters."),
+			},
+			"organization": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"handle_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultPluginAggregatorHandlePrefix,
+			},
+			"handle_suffix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"plugins": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"aggregators": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"plugin": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"handle": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"connections": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceWorkspacePluginAggregatorsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	diags := syncWorkspacePluginAggregators(ctx, meta.(*SteampipeClient), d, orgHandle, workspaceHandle, nil)
+	if diags.HasError() {
+		return diags
+	}
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	if orgHandle != "" {
+		d.SetId(fmt.Sprintf("%s/%s", orgHandle, workspaceHandle))
+	} else {
+		d.SetId(workspaceHandle)
+	}
+
+	return append(diags, resourceWorkspacePluginAggregatorsRead(ctx, d, meta)...)
+}
+
+// The naming arguments are not in state after an import, so the default handle_prefix is set for the aggregators
+// to be read. An empty handle_prefix is a valid setting, so the default cannot be applied in Read.
+func resourceWorkspacePluginAggregatorsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("handle_prefix", defaultPluginAggregatorHandlePrefix)
+	return importStatePassthroughByID(ctx, d, meta)
+}
+
+func resourceWorkspacePluginAggregatorsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	var orgHandle, workspaceHandle string
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	idParts := strings.Split(d.Id(), "/")
+	if len(idParts) == 2 {
+		orgHandle = idParts[0]
+		workspaceHandle = idParts[1]
+	} else if len(idParts) == 1 {
+		workspaceHandle = idParts[0]
+	} else {
+		return diag.Errorf("unexpected format of ID (%q), expected <workspace-handle> or <organization-handle>/<workspace-handle>", d.Id())
+	}
+
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		if isNotFound(r) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Workspace (%s) not found", workspaceHandle),
+			})
+			d.SetId("")
+			return diags
+		}
+		return diag.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	// Read the aggregators in state along with those the attached connections call for, so that an import
+	// picks up the existing aggregators
+	handles := map[string]bool{}
+	for _, aggregator := range d.Get("aggregators").([]interface{}) {
+		handles[aggregator.(map[string]interface{})["handle"].(string)] = true
+	}
+	for _, aggregator := range desiredPluginAggregators(d, workspaceConnectionsByHandle(attached)) {
+		handles[aggregator["handle"].(string)] = true
+	}
+
+	var aggregators []interface{}
+	for _, handle := range sortedKeys(handles) {
+		resp, r, err := getWorkspaceAggregator(ctx, client, orgHandle, workspaceHandle, handle)
+		if err != nil {
+			if isNotFound(r) {
+				continue
+			}
+			return diag.Errorf("error reading aggregator %s of workspace %s: %v", handle, workspaceHandle, decodeResponse(r))
+		}
+		aggregators = append(aggregators, flattenPluginAggregator(resp.Plugin, resp.Handle, resp.Connections))
+	}
+	sortPluginAggregators(aggregators)
+
+	d.Set("workspace_handle", workspaceHandle)
+	d.Set("organization", orgHandle)
+	if err := d.Set("aggregators", aggregators); err != nil {
+		return diag.Errorf("error setting aggregators: %v", err)
+	}
+
+	return diags
+}
+
+func resourceWorkspacePluginAggregatorsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	current, _ := d.GetChange("aggregators")
+	diags := syncWorkspacePluginAggregators(ctx, meta.(*SteampipeClient), d, orgHandle, workspaceHandle, current.([]interface{}))
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceWorkspacePluginAggregatorsRead(ctx, d, meta)...)
+}
+
+func resourceWorkspacePluginAggregatorsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+	var diags diag.Diagnostics
+
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	for _, aggregator := range d.Get("aggregators").([]interface{}) {
+		handle := aggregator.(map[string]interface{})["handle"].(string)
+		log.Printf("\n[DEBUG] Deleting Aggregator: %s for Workspace: %s", handle, workspaceHandle)
+		if r, err := deleteWorkspaceAggregator(ctx, client, orgHandle, workspaceHandle, handle); err != nil && !isNotFound(r) {
+			return diag.Errorf("error deleting aggregator %s: %v", handle, decodeResponse(r))
+		}
+	}
+	d.SetId("")
+
+	return diags
+}
+
+// resourceWorkspacePluginAggregatorsCustomizeDiff plans changes to the aggregators when connections have been
+// attached to or detached from the workspace since the last apply
+func resourceWorkspacePluginAggregatorsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, key := range []string{"handle_prefix", "handle_suffix", "plugins"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("aggregators")
+		}
+	}
+
+	workspaceHandle := d.Get("workspace_handle").(string)
+	orgHandle := d.Get("organization").(string)
+	attached, r, err := listWorkspaceConnections(ctx, meta.(*SteampipeClient), orgHandle, workspaceHandle)
+	if err != nil {
+		if isNotFound(r) {
+			return nil
+		}
+		return fmt.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	var desired []interface{}
+	for _, aggregator := range desiredPluginAggregators(d, workspaceConnectionsByHandle(attached)) {
+		desired = append(desired, aggregator)
+	}
+	if !reflect.DeepEqual(normalizePluginAggregators(d.Get("aggregators").([]interface{})), normalizePluginAggregators(desired)) {
+		return d.SetNew("aggregators", desired)
+	}
+	return nil
+}
+
+// syncWorkspacePluginAggregators creates, updates and deletes aggregators so that there is one aggregator for
+// each plugin of the connections attached to the workspace
+func syncWorkspacePluginAggregators(ctx context.Context, client *SteampipeClient, d *schema.ResourceData, orgHandle, workspaceHandle string, current []interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attached, r, err := listWorkspaceConnections(ctx, client, orgHandle, workspaceHandle)
+	if err != nil {
+		return diag.Errorf("error listing connections of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+	desired := desiredPluginAggregators(d, workspaceConnectionsByHandle(attached))
+
+	currentByHandle := map[string]map[string]interface{}{}
+	for _, aggregator := range normalizePluginAggregators(current) {
+		currentByHandle[aggregator["handle"].(string)] = aggregator
+	}
+
+	for _, aggregator := range desired {
+		handle := aggregator["handle"].(string)
+		plugin := aggregator["plugin"].(string)
+		connections := aggregator["connections"].([]string)
+
+		existing, ok := currentByHandle[handle]
+		delete(currentByHandle, handle)
+		if ok && existing["plugin"] == plugin && reflect.DeepEqual(existing["connections"], connections) {
+			continue
+		}
+
+		if ok {
+			log.Printf("\n[DEBUG] Updating Aggregator: %s for Workspace: %s with connections %v", handle, workspaceHandle, connections)
+			req := steampipe.UpdateWorkspaceAggregatorRequest{Connections: &connections}
+			if orgHandle == "" {
+				var actorHandle string
+				if actorHandle, r, err = getUserHandler(ctx, client); err == nil {
+					_, r, err = client.APIClient.UserWorkspaceAggregators.Update(ctx, actorHandle, workspaceHandle, handle).Request(req).Execute()
+				}
+			} else {
+				_, r, err = client.APIClient.OrgWorkspaceAggregators.Update(ctx, orgHandle, workspaceHandle, handle).Request(req).Execute()
+			}
+			if err != nil {
+				return append(diags, diag.Errorf("error updating aggregator %s: %v", handle, decodeResponse(r))...)
+			}
+			continue
+		}
+
+		log.Printf("\n[DEBUG] Creating Aggregator: %s for Workspace: %s with connections %v", handle, workspaceHandle, connections)
+		req := steampipe.CreateWorkspaceAggregatorRequest{Handle: handle, Plugin: plugin, Connections: connections}
+		if orgHandle == "" {
+			var actorHandle string
+			if actorHandle, r, err = getUserHandler(ctx, client); err == nil {
+				_, r, err = client.APIClient.UserWorkspaceAggregators.Create(ctx, actorHandle, workspaceHandle).Request(req).Execute()
+			}
+		} else {
+			_, r, err = client.APIClient.OrgWorkspaceAggregators.Create(ctx, orgHandle, workspaceHandle).Request(req).Execute()
+		}
+		if err != nil {
+			return append(diags, diag.Errorf("error creating aggregator %s: %v", handle, decodeResponse(r))...)
+		}
+	}
+
+	// Remove the aggregators of plugins which no longer have any connections attached
+	for _, handle := range sortedKeys(currentByHandle) {
+		log.Printf("\n[DEBUG] Deleting Aggregator: %s for Workspace: %s", handle, workspaceHandle)
+		if r, err := deleteWorkspaceAggregator(ctx, client, orgHandle, workspaceHandle, handle); err != nil && !isNotFound(r) {
+			return append(diags, diag.Errorf("error deleting aggregator %s: %v", handle, decodeResponse(r))...)
+		}
+	}
+
+	return diags
+}
+
+// desiredPluginAggregators returns an aggregator for each plugin of the attached connections, sorted by plugin
+func desiredPluginAggregators(d interface{ Get(string) interface{} }, attached map[string]steampipe.WorkspaceConn) []map[string]interface{} {
+	prefix := d.Get("handle_prefix").(string)
+	suffix := d.Get("handle_suffix").(string)
+	plugins := d.Get("plugins").(*schema.Set)
+
+	connectionsByPlugin := map[string][]string{}
+	for handle, association := range attached {
+		plugin := types.SafeString(association.Connection.Plugin)
+		if plugin == "" || plugins.Len() > 0 && !plugins.Contains(plugin) {
+			continue
+		}
+		connectionsByPlugin[plugin] = append(connectionsByPlugin[plugin], handle)
+	}
+
+	var aggregators []map[string]interface{}
+	for _, plugin := range sortedKeys(connectionsByPlugin) {
+		connections := connectionsByPlugin[plugin]
+		sort.Strings(connections)
+		aggregators = append(aggregators, map[string]interface{}{
+			"plugin":      plugin,
+			"handle":      pluginAggregatorHandle(prefix, plugin, suffix),
+			"connections": connections,
+		})
+	}
+	return aggregators
+}
+
+var pluginAggregatorHandleRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// pluginAggregatorHandle returns the aggregator handle for a plugin, e.g. all_aws, or all_github for turbot/github
+func pluginAggregatorHandle(prefix, plugin, suffix string) string {
+	name := connectionPluginName(plugin)
+	name = pluginAggregatorHandleRegex.ReplaceAllString(strings.ToLower(name), "_")
+	return prefix + name + suffix
+}
+
+func flattenPluginAggregator(plugin, handle string, connections []string) map[string]interface{} {
+	sorted := append([]string{}, connections...)
+	sort.Strings(sorted)
+	return map[string]interface{}{
+		"plugin":      plugin,
+		"handle":      handle,
+		"connections": sorted,
+	}
+}
+
+// normalizePluginAggregators converts aggregators read from state into the form returned by desiredPluginAggregators
+func normalizePluginAggregators(aggregators []interface{}) []map[string]interface{} {
+	normalized := []map[string]interface{}{}
+	for _, item := range aggregators {
+		aggregator := item.(map[string]interface{})
+		var connections []string
+		switch value := aggregator["connections"].(type) {
+		case []string:
+			connections = value
+		case []interface{}:
+			connections, _ = convertToStringArray(value)
+		}
+		normalized = append(normalized, flattenPluginAggregator(aggregator["plugin"].(string), aggregator["handle"].(string), connections))
+	}
+	return normalized
+}
+
+func sortPluginAggregators(aggregators []interface{}) {
+	sort.Slice(aggregators, func(i, j int) bool {
+		return aggregators[i].(map[string]interface{})["plugin"].(string) < aggregators[j].(map[string]interface{})["plugin"].(string)
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getWorkspaceAggregator(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle, aggregatorHandle string) (steampipe.WorkspaceAggregator, *http.Response, error) {
+	if orgHandle != "" {
+		return client.APIClient.OrgWorkspaceAggregators.Get(ctx, orgHandle, workspaceHandle, aggregatorHandle).Execute()
+	}
+	actorHandle, r, err := getUserHandler(ctx, client)
+	if err != nil {
+		return steampipe.WorkspaceAggregator{}, r, err
+	}
+	return client.APIClient.UserWorkspaceAggregators.Get(ctx, actorHandle, workspaceHandle, aggregatorHandle).Execute()
+}
+
+func deleteWorkspaceAggregator(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle, aggregatorHandle string) (*http.Response, error) {
+	if orgHandle != "" {
+		_, r, err := client.APIClient.OrgWorkspaceAggregators.Delete(ctx, orgHandle, workspaceHandle, aggregatorHandle).Execute()
+		return r, err
+	}
+	actorHandle, r, err := getUserHandler(ctx, client)
+	if err != nil {
+		return r, err
+	}
+	_, r, err = client.APIClient.UserWorkspaceAggregators.Delete(ctx, actorHandle, workspaceHandle, aggregatorHandle).Execute()
+	return r, err
+}
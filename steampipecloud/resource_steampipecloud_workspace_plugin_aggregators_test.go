@@ -0,0 +1,132 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/turbot/go-kit/types"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+func TestAccWorkspacePluginAggregators_Basic(t *testing.T) {
+	resourceName := "steampipecloud_workspace_plugin_aggregators.test"
+	workspaceHandle := "workspace" + randomString(6)
+	connHandle := "aws_" + randomString(4)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspacePluginAggregatorsConfig(workspaceHandle, connHandle),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace_handle", workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "aggregators.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "aggregators.0.plugin", "aws"),
+					resource.TestCheckResourceAttr(resourceName, "aggregators.0.handle", "all_aws"),
+					resource.TestCheckResourceAttr(resourceName, "aggregators.0.connections.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestDesiredPluginAggregators(t *testing.T) {
+	attached := workspaceConnectionsByHandle([]steampipe.WorkspaceConn{
+		{Connection: &steampipe.Connection{Handle: "aws_prod", Plugin: types.String("aws")}},
+		{Connection: &steampipe.Connection{Handle: "aws_dev", Plugin: types.String("aws")}},
+		{Connection: &steampipe.Connection{Handle: "github", Plugin: types.String("turbot/github")}},
+		{Connection: &steampipe.Connection{Handle: "all_aws", Plugin: types.String("aws"), Type: types.String("aggregator")}},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceWorkspacePluginAggregators().Schema, map[string]interface{}{
+		"workspace_handle": "dev",
+	})
+	require.Equal(t, []map[string]interface{}{
+		{"plugin": "aws", "handle": "all_aws", "connections": []string{"aws_dev", "aws_prod"}},
+		{"plugin": "turbot/github", "handle": "all_github", "connections": []string{"github"}},
+	}, desiredPluginAggregators(d, attached))
+
+	d = schema.TestResourceDataRaw(t, resourceWorkspacePluginAggregators().Schema, map[string]interface{}{
+		"workspace_handle": "dev",
+		"handle_prefix":    "",
+		"handle_suffix":    "_all",
+		"plugins":          []interface{}{"aws"},
+	})
+	require.Equal(t, []map[string]interface{}{
+		{"plugin": "aws", "handle": "aws_all", "connections": []string{"aws_dev", "aws_prod"}},
+	}, desiredPluginAggregators(d, attached))
+}
+
+func TestReadWorkspacePluginAggregatorsHandlePrefix(t *testing.T) {
+	client := newFakeServerClient(t, http.StatusOK)
+	r := resourceWorkspacePluginAggregators()
+
+	d := testResourceDataFromState(t, r, map[string]cty.Value{
+		"id":               cty.StringVal("dev"),
+		"workspace_handle": cty.StringVal("dev"),
+		"handle_prefix":    cty.StringVal(""),
+		"handle_suffix":    cty.StringVal("_all"),
+	})
+	require.Empty(t, r.ReadContext(context.Background(), d, client))
+	require.Equal(t, "", d.Get("handle_prefix"))
+
+	// the configured prefix is kept when Create reads the aggregators it created
+	for _, prefix := range []string{"", "org_"} {
+		d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"workspace_handle": "dev",
+			"handle_prefix":    prefix,
+		})
+		require.Empty(t, r.CreateContext(context.Background(), d, client))
+		require.Equal(t, prefix, d.Get("handle_prefix"))
+	}
+
+	// after an import only the ID is in state
+	d = testResourceDataFromState(t, r, map[string]cty.Value{"id": cty.StringVal("dev")})
+	imported, err := r.Importer.StateContext(context.Background(), d, client)
+	require.NoError(t, err)
+	require.Empty(t, r.ReadContext(context.Background(), imported[0], client))
+	require.Equal(t, defaultPluginAggregatorHandlePrefix, imported[0].Get("handle_prefix"))
+}
+
+func testAccWorkspacePluginAggregatorsConfig(workspace string, conn string) string {
+	return fmt.Sprintf(`
+provider "steampipecloud" {}
+
+resource "steampipecloud_workspace" "test" {
+  handle = "%[1]s"
+}
+
+resource "steampipecloud_connection" "test" {
+	for_each = toset(["%[2]s", "%[2]s_2"])
+	handle   = each.key
+	plugin   = "aws"
+	config = jsonencode({
+		regions    = ["us-east-1"]
+		access_key = "redacted"
+		secret_key = "redacted"
+	})
+}
+
+resource "steampipecloud_workspace_connections" "test" {
+  workspace_handle   = steampipecloud_workspace.test.handle
+  connection_handles = [for conn in steampipecloud_connection.test : conn.handle]
+}
+
+resource "steampipecloud_workspace_plugin_aggregators" "test" {
+  workspace_handle = steampipecloud_workspace.test.handle
+
+  depends_on = [steampipecloud_workspace_connections.test]
+}`, workspace, conn)
+}
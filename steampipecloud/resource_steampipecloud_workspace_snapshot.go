@@ -2,7 +2,6 @@ package steampipecloud
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -24,6 +23,7 @@ func resourceWorkspaceSnapshot() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceWorkspaceSnapshotDataCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"workspace_snapshot_id": {
 				Type:     schema.TypeString,
@@ -46,8 +46,17 @@ func resourceWorkspaceSnapshot() *schema.Resource {
 				Computed: true,
 			},
 			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(snapshotVisibilities, false),
+			},
+			"share_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dashboard_url": {
 				Type:     schema.TypeString,
-				Optional: true,
 				Computed: true,
 			},
 			"dashboard_name": {
@@ -85,9 +94,9 @@ func resourceWorkspaceSnapshot() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			// Steampipe Cloud sets the expiry of a snapshot, and ignores an expiry sent with it
 			"expires_at": {
 				Type:     schema.TypeString,
-				Optional: true,
 				Computed: true,
 			},
 			"created_by": {
@@ -116,8 +125,19 @@ func resourceWorkspaceSnapshot() *schema.Resource {
 			},
 			"data": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validation.StringIsJSON,
+				ExactlyOneOf: []string{"data", "data_file"},
+			},
+			"data_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ExactlyOneOf: []string{"data", "data_file"},
+			},
+			"data_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
@@ -131,12 +151,11 @@ func resourceWorkspaceSnapshotCreate(ctx context.Context, d *schema.ResourceData
 	var err error
 	var r *http.Response
 	var resp steampipe.WorkspaceSnapshot
-	var data steampipe.WorkspaceSnapshotData
 
 	workspaceHandle := d.Get("workspace_handle").(string)
-	err = json.Unmarshal([]byte(d.Get("data").(string)), &data)
+	content, data, err := getSnapshotData(d)
 	if err != nil {
-		return diag.Errorf("error parsing data for workspace snapshot : %v", d.Get("data").(string))
+		return diag.Errorf("error parsing data for workspace snapshot: %v", err)
 	}
 	tags, err := JSONStringToInterface(d.Get("tags").(string))
 	if err != nil {
@@ -149,8 +168,8 @@ func resourceWorkspaceSnapshotCreate(ctx context.Context, d *schema.ResourceData
 	req := steampipe.CreateWorkspaceSnapshotRequest{Data: data, Tags: tags, Visibility: &visibility}
 
 	isUser, orgHandle := isUserConnection(d)
+	var userHandle string
 	if isUser {
-		var userHandle string
 		userHandle, r, err = getUserHandler(ctx, client)
 		if err != nil {
 			return diag.Errorf("resourceWorkspaceSnapshotCreate.getUserHandler error  %v", decodeResponse(r))
@@ -180,6 +199,9 @@ func resourceWorkspaceSnapshotCreate(ctx context.Context, d *schema.ResourceData
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	d.Set("expires_at", resp.ExpiresAt)
+	shareURL, dashboardURL := snapshotURLs(consoleURL(client), userHandle, orgHandle, workspaceHandle, resp)
+	d.Set("share_url", shareURL)
+	d.Set("dashboard_url", dashboardURL)
 	if resp.CreatedBy != nil {
 		d.Set("created_by", resp.CreatedBy.Handle)
 	}
@@ -189,6 +211,7 @@ func resourceWorkspaceSnapshotCreate(ctx context.Context, d *schema.ResourceData
 	d.Set("version_id", resp.VersionId)
 	d.Set("organization", orgHandle)
 	d.Set("workspace_handle", workspaceHandle)
+	d.Set("data_sha256", snapshotDataSha256(content))
 
 	// If snapshot is created for a workspace inside an Organization the id will be of the
 	// format "OrganizationHandle/WorkspaceHandle/SnapshotID" otherwise "WorkspaceHandle/SnapshotID"
@@ -230,8 +253,8 @@ func resourceWorkspaceSnapshotRead(ctx context.Context, d *schema.ResourceData,
 	var err error
 	var r *http.Response
 
+	var userHandle string
 	if isUser {
-		var userHandle string
 		userHandle, r, err = getUserHandler(ctx, client)
 		if err != nil {
 			return diag.Errorf("resourceWorkspaceSnapshotRead.getUserHandler error  %v", decodeResponse(r))
@@ -243,6 +266,9 @@ func resourceWorkspaceSnapshotRead(ctx context.Context, d *schema.ResourceData,
 
 	// Error check
 	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace snapshot (%s) not found in workspace (%s)", snapshotId, workspaceHandle))
+		}
 		return diag.Errorf("error getting workspace snapshot: %v", decodeResponse(r))
 	}
 	log.Printf("\n[DEBUG] Snapshot: %s received for Workspace: %s", resp.Id, workspaceHandle)
@@ -261,6 +287,9 @@ func resourceWorkspaceSnapshotRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	d.Set("expires_at", resp.ExpiresAt)
+	shareURL, dashboardURL := snapshotURLs(consoleURL(client), userHandle, orgHandle, workspaceHandle, resp)
+	d.Set("share_url", shareURL)
+	d.Set("dashboard_url", dashboardURL)
 	if resp.CreatedBy != nil {
 		d.Set("created_by", resp.CreatedBy.Handle)
 	}
@@ -270,6 +299,11 @@ func resourceWorkspaceSnapshotRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("version_id", resp.VersionId)
 	d.Set("organization", orgHandle)
 	d.Set("workspace_handle", workspaceHandle)
+	// Snapshots created before data_sha256 was added have no hash, so it is taken from the data in state to
+	// avoid planning a change after an upgrade
+	if data := d.Get("data").(string); data != "" && d.Get("data_sha256").(string) == "" {
+		d.Set("data_sha256", snapshotDataSha256([]byte(data)))
+	}
 
 	// If snapshot is created for a workspace inside an Organization the id will be of the
 	// format "OrganizationHandle/WorkspaceHandle/SnapshotID" otherwise "WorkspaceHandle/SnapshotID"
@@ -303,8 +337,8 @@ func resourceWorkspaceSnapshotUpdate(ctx context.Context, d *schema.ResourceData
 	req := steampipe.UpdateWorkspaceSnapshotRequest{Tags: tags, Visibility: &visibility}
 
 	isUser, orgHandle := isUserConnection(d)
+	var userHandle string
 	if isUser {
-		var userHandle string
 		userHandle, r, err = getUserHandler(ctx, client)
 		if err != nil {
 			return diag.Errorf("resourceWorkspaceSnapshotUpdate.getUserHandler error  %v", decodeResponse(r))
@@ -334,6 +368,9 @@ func resourceWorkspaceSnapshotUpdate(ctx context.Context, d *schema.ResourceData
 	d.Set("created_at", resp.CreatedAt)
 	d.Set("updated_at", resp.UpdatedAt)
 	d.Set("expires_at", resp.ExpiresAt)
+	shareURL, dashboardURL := snapshotURLs(consoleURL(client), userHandle, orgHandle, workspaceHandle, resp)
+	d.Set("share_url", shareURL)
+	d.Set("dashboard_url", dashboardURL)
 	if resp.CreatedBy != nil {
 		d.Set("created_by", resp.CreatedBy.Handle)
 	}
@@ -355,6 +392,23 @@ func resourceWorkspaceSnapshotUpdate(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
+// snapshotURLs returns the link to share a snapshot with, which is only set if anyone with the link can view it,
+// and the URL of the dashboard the snapshot was taken of. The snapshot belongs to the organization if orgHandle is
+// set, otherwise to the user with userHandle.
+func snapshotURLs(consoleURL, userHandle, orgHandle, workspaceHandle string, snapshot steampipe.WorkspaceSnapshot) (shareURL, dashboardURL string) {
+	workspaceURL := fmt.Sprintf("%s/user/%s/workspace/%s", consoleURL, userHandle, workspaceHandle)
+	if orgHandle != "" {
+		workspaceURL = fmt.Sprintf("%s/org/%s/workspace/%s", consoleURL, orgHandle, workspaceHandle)
+	}
+	if snapshot.Visibility != nil && *snapshot.Visibility == snapshotVisibilityAnyoneWithLink {
+		shareURL = fmt.Sprintf("%s/snapshot/%s", workspaceURL, snapshot.Id)
+	}
+	if snapshot.DashboardName != "" {
+		dashboardURL = fmt.Sprintf("%s/dashboard/%s", workspaceURL, snapshot.DashboardName)
+	}
+	return shareURL, dashboardURL
+}
+
 func resourceWorkspaceSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*SteampipeClient)
 
@@ -0,0 +1,107 @@
+package steampipecloud
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// Some editors write a byte order mark at the start of UTF-8 files, which is not valid JSON
+var utf8ByteOrderMark = []byte("\xef\xbb\xbf")
+
+// readSnapshotDataFile reads the snapshot data in a file, e.g. a .sps file written by `steampipe dashboard --export`
+func readSnapshotDataFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot data file %s: %v", path, err)
+	}
+	return bytes.TrimPrefix(content, utf8ByteOrderMark), nil
+}
+
+// parseSnapshotData parses snapshot data. Fields which are not part of the API snapshot data, e.g. the
+// search_path included in .sps files, are ignored.
+func parseSnapshotData(content []byte) (steampipe.WorkspaceSnapshotData, error) {
+	var data steampipe.WorkspaceSnapshotData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return data, fmt.Errorf("snapshot data is not valid JSON: %v", err)
+	}
+	return data, nil
+}
+
+// validateSnapshotDataFile checks that the data read from a data_file is a dashboard snapshot, since any JSON file
+// parses as snapshot data. Inline data is left for the API to validate, as it was before data_file was added.
+func validateSnapshotDataFile(data steampipe.WorkspaceSnapshotData) error {
+	if data.SchemaVersion == "" {
+		return fmt.Errorf("snapshot data must include a schema_version")
+	}
+	if data.Panels == nil {
+		return fmt.Errorf("snapshot data must include panels")
+	}
+	if data.Layout.Name == "" {
+		return fmt.Errorf("snapshot data must include a layout naming the dashboard")
+	}
+	return nil
+}
+
+// snapshotDataSha256 returns the hex encoded SHA-256 hash of the snapshot data
+func snapshotDataSha256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// getSnapshotData returns the content of the data argument, or of the file named by data_file, along with the
+// parsed snapshot data
+func getSnapshotData(d interface{ Get(string) interface{} }) ([]byte, steampipe.WorkspaceSnapshotData, error) {
+	path := d.Get("data_file").(string)
+	if path == "" {
+		content := []byte(d.Get("data").(string))
+		data, err := parseSnapshotData(content)
+		return content, data, err
+	}
+
+	content, err := readSnapshotDataFile(path)
+	if err != nil {
+		return nil, steampipe.WorkspaceSnapshotData{}, err
+	}
+	data, err := parseSnapshotData(content)
+	if err == nil {
+		err = validateSnapshotDataFile(data)
+	}
+	if err != nil {
+		return nil, data, fmt.Errorf("invalid snapshot data file %s: %v", path, err)
+	}
+	return content, data, nil
+}
+
+// resourceWorkspaceSnapshotDataCustomizeDiff validates the snapshot data during plan, and replaces the snapshot
+// when the hash of its data changes, since the data of a snapshot cannot be updated
+func resourceWorkspaceSnapshotDataCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("data") || !d.NewValueKnown("data_file") {
+		return d.SetNewComputed("data_sha256")
+	}
+	content, _, err := getSnapshotData(d)
+	if err != nil {
+		return err
+	}
+
+	hash := snapshotDataSha256(content)
+	oldHash, _ := d.GetChange("data_sha256")
+	if oldHash.(string) == hash {
+		return nil
+	}
+	if err := d.SetNew("data_sha256", hash); err != nil {
+		return err
+	}
+	// Snapshots created before data_sha256 was added have no hash until they are refreshed, so they are not replaced
+	if d.Id() != "" && oldHash.(string) != "" {
+		return d.ForceNew("data_sha256")
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package steampipecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+const (
+	snapshotRetentionGroupByDashboard = "dashboard"
+	snapshotRetentionGroupByTag       = "tag"
+)
+
+func resourceWorkspaceSnapshotRetention() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWorkspaceSnapshotRetentionCreate,
+		ReadContext:   resourceWorkspaceSnapshotRetentionRead,
+		UpdateContext: resourceWorkspaceSnapshotRetentionUpdate,
+		DeleteContext: resourceWorkspaceSnapshotRetentionDelete,
+		CustomizeDiff: resourceWorkspaceSnapshotRetentionCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStatePassthroughByID,
+		},
+		Schema: map[string]*schema.Schema{
+			"workspace_handle": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z0-9]{1,23}$`), "Handle must be between 1 and 23 characters, and may only contain alphanumeric characters."),
+			},
+			"organization": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"keep": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"group_by": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      snapshotRetentionGroupByDashboard,
+				ValidateFunc: validation.StringInSlice([]string{snapshotRetentionGroupByDashboard, snapshotRetentionGroupByTag}, false),
+			},
+			"tag": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"prune_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"pruned_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceWorkspaceSnapshotRetentionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	diags := pruneWorkspaceSnapshots(ctx, meta.(*SteampipeClient), d, orgHandle, workspaceHandle)
+	if diags.HasError() {
+		return diags
+	}
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	if orgHandle != "" {
+		d.SetId(fmt.Sprintf("%s/%s", orgHandle, workspaceHandle))
+	} else {
+		d.SetId(workspaceHandle)
+	}
+
+	return append(diags, resourceWorkspaceSnapshotRetentionRead(ctx, d, meta)...)
+}
+
+func resourceWorkspaceSnapshotRetentionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*SteampipeClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	var orgHandle, workspaceHandle string
+
+	// If the workspace exists inside an Organization the id will be of the
+	// format "OrganizationHandle/WorkspaceHandle" otherwise "WorkspaceHandle"
+	idParts := strings.Split(d.Id(), "/")
+	if len(idParts) == 2 {
+		orgHandle = idParts[0]
+		workspaceHandle = idParts[1]
+	} else if len(idParts) == 1 {
+		workspaceHandle = idParts[0]
+	} else {
+		return diag.Errorf("unexpected format of ID (%q), expected <workspace-handle> or <organization-handle>/<workspace-handle>", d.Id())
+	}
+
+	var r *http.Response
+	var err error
+	if orgHandle == "" {
+		var actorHandle string
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return diag.Errorf("resourceWorkspaceSnapshotRetentionRead.getUserHandler error  %v", decodeResponse(r))
+		}
+		_, r, err = client.APIClient.UserWorkspaces.Get(ctx, actorHandle, workspaceHandle).Execute()
+	} else {
+		_, r, err = client.APIClient.OrgWorkspaces.Get(ctx, orgHandle, workspaceHandle).Execute()
+	}
+	if err != nil {
+		if isNotFound(r) {
+			return resourceNotFound(d, fmt.Sprintf("Workspace (%s) not found", workspaceHandle))
+		}
+		return diag.Errorf("error reading workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	// The retention arguments are not in state after an import
+	if _, ok := d.GetOk("group_by"); !ok {
+		d.Set("group_by", snapshotRetentionGroupByDashboard)
+	}
+	d.Set("workspace_handle", workspaceHandle)
+	d.Set("organization", orgHandle)
+
+	return diags
+}
+
+func resourceWorkspaceSnapshotRetentionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	workspaceHandle := d.Get("workspace_handle").(string)
+	_, orgHandle := isUserConnection(d)
+
+	diags := pruneWorkspaceSnapshots(ctx, meta.(*SteampipeClient), d, orgHandle, workspaceHandle)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceWorkspaceSnapshotRetentionRead(ctx, d, meta)...)
+}
+
+// Snapshots are left as they are when the retention policy is removed
+func resourceWorkspaceSnapshotRetentionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// resourceWorkspaceSnapshotRetentionCustomizeDiff plans how many snapshots the next apply will delete, so that
+// snapshots taken since the last apply are planned to be pruned
+func resourceWorkspaceSnapshotRetentionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	groupBy := d.Get("group_by").(string)
+	tag := d.Get("tag").(string)
+	if d.NewValueKnown("group_by") && d.NewValueKnown("tag") {
+		if groupBy == snapshotRetentionGroupByTag && tag == "" {
+			return fmt.Errorf("tag must be set when group_by is %q", snapshotRetentionGroupByTag)
+		}
+		if groupBy != snapshotRetentionGroupByTag && tag != "" {
+			return fmt.Errorf("tag can only be set when group_by is %q", snapshotRetentionGroupByTag)
+		}
+	}
+	for _, key := range []string{"workspace_handle", "organization", "keep", "group_by", "tag"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("prune_count")
+		}
+	}
+
+	workspaceHandle := d.Get("workspace_handle").(string)
+	orgHandle := d.Get("organization").(string)
+	snapshots, r, err := listWorkspaceSnapshots(ctx, meta.(*SteampipeClient), orgHandle, workspaceHandle, snapshotRetentionWhere(groupBy, tag))
+	if err != nil {
+		if isNotFound(r) {
+			return d.SetNew("prune_count", 0)
+		}
+		return fmt.Errorf("error listing snapshots of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	pruneCount := len(snapshotsToPrune(snapshots, d.Get("keep").(int), groupBy, tag))
+	log.Printf("\n[DEBUG] %d snapshots of workspace %s to prune", pruneCount, workspaceHandle)
+	if pruneCount > 0 {
+		if err := d.SetNew("prune_count", pruneCount); err != nil {
+			return err
+		}
+		return d.SetNewComputed("pruned_at")
+	}
+	if d.Id() == "" || d.HasChanges("keep", "group_by", "tag") {
+		return d.SetNew("prune_count", 0)
+	}
+	return nil
+}
+
+// pruneWorkspaceSnapshots deletes the snapshots of a workspace that are not retained by the policy
+func pruneWorkspaceSnapshots(ctx context.Context, client *SteampipeClient, d *schema.ResourceData, orgHandle, workspaceHandle string) diag.Diagnostics {
+	groupBy := d.Get("group_by").(string)
+	tag := d.Get("tag").(string)
+	snapshots, r, err := listWorkspaceSnapshots(ctx, client, orgHandle, workspaceHandle, snapshotRetentionWhere(groupBy, tag))
+	if err != nil {
+		return diag.Errorf("error listing snapshots of workspace %s: %v", workspaceHandle, decodeResponse(r))
+	}
+
+	pruned := snapshotsToPrune(snapshots, d.Get("keep").(int), groupBy, tag)
+	for _, snapshot := range pruned {
+		log.Printf("\n[DEBUG] Pruning snapshot: %s of dashboard: %s for workspace: %s", snapshot.Id, snapshot.DashboardName, workspaceHandle)
+		if r, err := deleteWorkspaceSnapshot(ctx, client, orgHandle, workspaceHandle, snapshot.Id); err != nil && !isNotFound(r) {
+			return diag.Errorf("error deleting snapshot %s: %v", snapshot.Id, decodeResponse(r))
+		}
+	}
+
+	d.Set("prune_count", len(pruned))
+	if len(pruned) > 0 {
+		d.Set("pruned_at", time.Now().UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// snapshotRetentionWhere returns the where clause which limits the listed snapshots to those the policy applies to.
+// Every snapshot of the workspace belongs to a dashboard, so only snapshots grouped by tag are filtered.
+func snapshotRetentionWhere(groupBy, tag string) string {
+	if groupBy != snapshotRetentionGroupByTag {
+		return ""
+	}
+	return fmt.Sprintf("tags ->> %s is not null", sqlQuote(tag))
+}
+
+// snapshotsToPrune returns the snapshots which are not among the keep most recent snapshots of their group.
+// Snapshots are grouped by dashboard, or by the value of a tag, in which case snapshots without the tag are retained.
+func snapshotsToPrune(snapshots []steampipe.WorkspaceSnapshot, keep int, groupBy, tag string) []steampipe.WorkspaceSnapshot {
+	groups := map[string][]steampipe.WorkspaceSnapshot{}
+	for _, snapshot := range snapshots {
+		group := snapshot.DashboardName
+		if groupBy == snapshotRetentionGroupByTag {
+			tags, _ := snapshot.Tags.(map[string]interface{})
+			value, ok := tags[tag]
+			if !ok || value == nil {
+				continue
+			}
+			group = fmt.Sprintf("%v", value)
+		}
+		groups[group] = append(groups[group], snapshot)
+	}
+
+	var pruned []steampipe.WorkspaceSnapshot
+	for _, group := range sortedKeys(groups) {
+		snapshots := groups[group]
+		sort.SliceStable(snapshots, func(i, j int) bool {
+			return snapshots[i].CreatedAt > snapshots[j].CreatedAt
+		})
+		if len(snapshots) > keep {
+			pruned = append(pruned, snapshots[keep:]...)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,120 @@
+package steampipecloud
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
+)
+
+// test suites
+func TestAccUserWorkspaceSnapshotRetention_Basic(t *testing.T) {
+	resourceName := "steampipecloud_workspace_snapshot_retention.retention"
+	workspaceHandle := "workspace" + randomString(3)
+	dataFile := filepath.Join(t.TempDir(), "aws_s3_bucket_dashboard.sps")
+	if err := os.WriteFile(dataFile, []byte(testSnapshotData), 0600); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspaceSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserWorkspaceSnapshotRetentionConfig(workspaceHandle, dataFile, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("steampipecloud_workspace_snapshot.snapshots.0", "state", "available"),
+				),
+			},
+			{
+				// The snapshots pruned by the retention policy are planned to be created again
+				Config: testAccUserWorkspaceSnapshotRetentionConfig(workspaceHandle, dataFile, `
+				resource "steampipecloud_workspace_snapshot_retention" "retention" {
+					workspace_handle = steampipecloud_workspace.test_workspace.handle
+					keep             = 1
+				}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "prune_count", "2"),
+					resource.TestCheckResourceAttr(resourceName, "group_by", "dashboard"),
+					resource.TestCheckResourceAttrSet(resourceName, "pruned_at"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserWorkspaceSnapshotRetentionConfig(workspaceHandle, dataFile, `
+				resource "steampipecloud_workspace_snapshot_retention" "retention" {
+					workspace_handle = steampipecloud_workspace.test_workspace.handle
+					keep             = 1
+					group_by         = "tag"
+				}`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`tag must be set when group_by is "tag"`),
+			},
+		},
+	})
+}
+
+func testAccUserWorkspaceSnapshotRetentionConfig(workspaceHandle, dataFile, retention string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_snapshot" "snapshots" {
+		count            = 3
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		data_file        = "%s"
+		visibility       = "workspace"
+	}
+	%s`, workspaceHandle, dataFile, retention)
+}
+
+func TestSnapshotRetentionWhere(t *testing.T) {
+	if where := snapshotRetentionWhere(snapshotRetentionGroupByDashboard, ""); where != "" {
+		t.Errorf("snapshotRetentionWhere for dashboard returned %q, expected no filter", where)
+	}
+	if where := snapshotRetentionWhere(snapshotRetentionGroupByTag, "o'clock"); where != "tags ->> 'o''clock' is not null" {
+		t.Errorf("snapshotRetentionWhere for tag returned %q", where)
+	}
+}
+
+func TestSnapshotsToPrune(t *testing.T) {
+	snapshot := func(id, dashboard, createdAt string, tags map[string]interface{}) steampipe.WorkspaceSnapshot {
+		return steampipe.WorkspaceSnapshot{Id: id, DashboardName: dashboard, CreatedAt: createdAt, Tags: tags}
+	}
+	snapshots := []steampipe.WorkspaceSnapshot{
+		snapshot("snap_1", "aws_compliance.benchmark.cis_v140", "2022-08-01T10:00:00Z", map[string]interface{}{"series": "daily_cis"}),
+		snapshot("snap_2", "aws_compliance.benchmark.cis_v140", "2022-08-03T10:00:00Z", map[string]interface{}{"series": "daily_cis"}),
+		snapshot("snap_3", "aws_compliance.benchmark.cis_v140", "2022-08-02T10:00:00Z", nil),
+		snapshot("snap_4", "aws_insights.dashboard.aws_s3_bucket_dashboard", "2022-08-01T10:00:00Z", map[string]interface{}{"series": "daily_cis"}),
+		snapshot("snap_5", "aws_insights.dashboard.aws_s3_bucket_dashboard", "2022-08-02T10:00:00Z", map[string]interface{}{"series": "weekly"}),
+	}
+	tests := []struct {
+		keep     int
+		groupBy  string
+		tag      string
+		expected []string
+	}{
+		{1, snapshotRetentionGroupByDashboard, "", []string{"snap_3", "snap_1", "snap_4"}},
+		{2, snapshotRetentionGroupByDashboard, "", []string{"snap_1"}},
+		{3, snapshotRetentionGroupByDashboard, "", nil},
+		{1, snapshotRetentionGroupByTag, "series", []string{"snap_1", "snap_4"}},
+		{1, snapshotRetentionGroupByTag, "missing", nil},
+	}
+	for _, test := range tests {
+		var ids []string
+		for _, snapshot := range snapshotsToPrune(snapshots, test.keep, test.groupBy, test.tag) {
+			ids = append(ids, snapshot.Id)
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("snapshotsToPrune(keep %d, %s %s) returned %v, expected %v", test.keep, test.groupBy, test.tag, ids, test.expected)
+		}
+	}
+}
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	steampipe "github.com/turbot/steampipe-cloud-sdk-go"
 )
 
 // test suites
@@ -28,13 +34,15 @@ func TestAccUserWorkspaceSnapshot_Basic(t *testing.T) {
 					testAccCheckWorkspaceSnapshotExists(workspaceHandle),
 					resource.TestCheckResourceAttr(resourceName, "state", "available"),
 					resource.TestCheckResourceAttr(resourceName, "visibility", visibility),
+					resource.TestCheckResourceAttr(resourceName, "share_url", ""),
+					resource.TestMatchResourceAttr(resourceName, "dashboard_url", regexp.MustCompile(`^https://cloud\.steampipe\.io/user/[a-z0-9-]+/workspace/`+workspaceHandle+`/dashboard/aws_insights\.dashboard\.aws_s3_bucket_dashboard$`)),
 				),
 			},
 			{
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"updated_at", "data"},
+				ImportStateVerifyIgnore: []string{"updated_at", "data", "data_sha256"},
 			},
 			{
 				Config: testAccUserWorkspaceSnapshotUpdateConfig(workspaceHandle, updatedVisibility),
@@ -42,12 +50,60 @@ func TestAccUserWorkspaceSnapshot_Basic(t *testing.T) {
 					testAccCheckWorkspaceSnapshotExists(workspaceHandle),
 					resource.TestCheckResourceAttr(resourceName, "state", "available"),
 					resource.TestCheckResourceAttr(resourceName, "visibility", updatedVisibility),
+					resource.TestMatchResourceAttr(resourceName, "share_url", regexp.MustCompile(`^https://cloud\.steampipe\.io/user/[a-z0-9-]+/workspace/`+workspaceHandle+`/snapshot/snap_[0-9a-v]{20}_[0-9a-z]+$`)),
 				),
 			},
 		},
 	})
 }
 
+func TestAccUserWorkspaceSnapshot_DataFile(t *testing.T) {
+	resourceName := "steampipecloud_workspace_snapshot.snapshot_1"
+	workspaceHandle := "workspace" + randomString(3)
+	dataFile := filepath.Join(t.TempDir(), "aws_s3_bucket_dashboard.sps")
+	if err := os.WriteFile(dataFile, []byte(testSnapshotData), 0600); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWorkspaceSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserWorkspaceSnapshotDataFileConfig(workspaceHandle, dataFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWorkspaceSnapshotExists(workspaceHandle),
+					resource.TestCheckResourceAttr(resourceName, "state", "available"),
+					resource.TestCheckResourceAttr(resourceName, "data_file", dataFile),
+					resource.TestCheckResourceAttr(resourceName, "data_sha256", snapshotDataSha256([]byte(testSnapshotData))),
+					resource.TestCheckNoResourceAttr(resourceName, "data"),
+				),
+			},
+			{
+				// Changing the content of the file replaces the snapshot
+				PreConfig: func() {
+					if err := os.WriteFile(dataFile, []byte(strings.Replace(testSnapshotData, "2022-12-16T10:42:29Z", "2022-12-17T10:42:29Z", 1)), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccUserWorkspaceSnapshotDataFileConfig(workspaceHandle, dataFile),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(dataFile, []byte(`{"schema_version": "20220929"}`), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccUserWorkspaceSnapshotDataFileConfig(workspaceHandle, dataFile),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`invalid snapshot data file`),
+			},
+		},
+	})
+}
+
 func testAccUserWorkspaceSnapshotConfig(workspaceHandle, visibility string) string {
 	return fmt.Sprintf(`
 	provider "steampipecloud" {}
@@ -299,6 +355,166 @@ func testAccUserWorkspaceSnapshotUpdateConfig(workspaceHandle, visibility string
 	}`, workspaceHandle, visibility)
 }
 
+func testAccUserWorkspaceSnapshotDataFileConfig(workspaceHandle, dataFile string) string {
+	return fmt.Sprintf(`
+	provider "steampipecloud" {}
+
+	resource "steampipecloud_workspace" "test_workspace" {
+		handle = "%s"
+	}
+
+	resource "steampipecloud_workspace_snapshot" "snapshot_1" {
+		workspace_handle = steampipecloud_workspace.test_workspace.handle
+		data_file        = "%s"
+		visibility       = "workspace"
+	}`, workspaceHandle, dataFile)
+}
+
+// A snapshot as written by `steampipe dashboard --export sps`
+const testSnapshotData = `{
+	"schema_version": "20220929",
+	"start_time": "2022-12-16T10:42:23Z",
+	"end_time": "2022-12-16T10:42:29Z",
+	"search_path": ["public", "aws"],
+	"layout": {
+		"name": "aws_insights.dashboard.aws_s3_bucket_dashboard",
+		"panel_type": "dashboard"
+	},
+	"panels": {
+		"aws_insights.dashboard.aws_s3_bucket_dashboard": {
+			"dashboard": "aws_insights.dashboard.aws_s3_bucket_dashboard",
+			"name": "aws_insights.dashboard.aws_s3_bucket_dashboard",
+			"panel_type": "dashboard",
+			"status": "complete",
+			"title": "AWS S3 Bucket Dashboard"
+		}
+	},
+	"inputs": {},
+	"variables": {}
+}`
+
+func TestSnapshotURLs(t *testing.T) {
+	anyoneWithLink, workspace := snapshotVisibilityAnyoneWithLink, snapshotVisibilityWorkspace
+	snapshot := steampipe.WorkspaceSnapshot{
+		Id:            "snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl",
+		DashboardName: "aws_insights.dashboard.aws_s3_bucket_dashboard",
+		Visibility:    &anyoneWithLink,
+	}
+
+	shareURL, dashboardURL := snapshotURLs(defaultConsoleURL, "testuser", "", "dev", snapshot)
+	if shareURL != "https://cloud.steampipe.io/user/testuser/workspace/dev/snapshot/snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl" {
+		t.Errorf("snapshotURLs returned share URL %s", shareURL)
+	}
+	if dashboardURL != "https://cloud.steampipe.io/user/testuser/workspace/dev/dashboard/aws_insights.dashboard.aws_s3_bucket_dashboard" {
+		t.Errorf("snapshotURLs returned dashboard URL %s", dashboardURL)
+	}
+
+	snapshot.Visibility = &workspace
+	shareURL, dashboardURL = snapshotURLs("https://cloud.dev.steampipe.io", "", "testorg", "dev", snapshot)
+	if shareURL != "" {
+		t.Errorf("snapshotURLs returned share URL %s for a workspace snapshot", shareURL)
+	}
+	if dashboardURL != "https://cloud.dev.steampipe.io/org/testorg/workspace/dev/dashboard/aws_insights.dashboard.aws_s3_bucket_dashboard" {
+		t.Errorf("snapshotURLs returned dashboard URL %s", dashboardURL)
+	}
+}
+
+func TestConsoleURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                      "https://cloud.steampipe.io",
+		"https://cloud.dev.steampipe.io":        "https://cloud.dev.steampipe.io",
+		"https://cloud.dev.steampipe.io/api/v0": "https://cloud.dev.steampipe.io",
+		"cloud.dev.steampipe.io":                "https://cloud.steampipe.io",
+	}
+	for host, expected := range tests {
+		if url := consoleURL(&SteampipeClient{Config: &Config{Host: host}}); url != expected {
+			t.Errorf("consoleURL for host %q returned %s, expected %s", host, url, expected)
+		}
+	}
+}
+
+func TestParseSnapshotData(t *testing.T) {
+	tests := []struct {
+		data  string
+		valid bool
+	}{
+		{testSnapshotData, true},
+		{`{"schema_version": "20220929", "panels": {}, "layout": {"name": "mod.dashboard.empty", "panel_type": "dashboard"}}`, true},
+		{`not json`, false},
+		{`{"panels": {}, "layout": {"name": "mod.dashboard.empty"}}`, false},
+		{`{"schema_version": "20220929", "layout": {"name": "mod.dashboard.empty"}}`, false},
+		{`{"schema_version": "20220929", "panels": {}}`, false},
+	}
+	for _, test := range tests {
+		data, err := parseSnapshotData([]byte(test.data))
+		if err == nil {
+			err = validateSnapshotDataFile(data)
+		}
+		if (err == nil) != test.valid {
+			t.Errorf("parseSnapshotData(%s) returned %v, expected valid %t", test.data, err, test.valid)
+		}
+	}
+}
+
+func TestGetSnapshotData(t *testing.T) {
+	r := resourceWorkspaceSnapshot()
+
+	// inline data is only checked to be JSON, as before data_file was added
+	d := r.TestResourceData()
+	d.Set("data", `{"schema_version": "20220929"}`)
+	if _, _, err := getSnapshotData(d); err != nil {
+		t.Errorf("getSnapshotData returned %v for inline data", err)
+	}
+
+	dataFile := filepath.Join(t.TempDir(), "dashboard.sps")
+	if err := os.WriteFile(dataFile, []byte(`{"schema_version": "20220929"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d = r.TestResourceData()
+	d.Set("data_file", dataFile)
+	if _, _, err := getSnapshotData(d); err == nil {
+		t.Errorf("getSnapshotData returned no error for a data file without panels")
+	}
+}
+
+func TestReadWorkspaceSnapshotDataSha256(t *testing.T) {
+	client := newFakeServerClient(t, http.StatusOK)
+	r := resourceWorkspaceSnapshot()
+
+	// snapshots created before data_sha256 was added get the hash of the data in state
+	d := testResourceDataFromState(t, r, map[string]cty.Value{
+		"id":   cty.StringVal("dev/snap_cbqgah8smpv7n7sg9o0g_2jh0oc9dg1ums4sxb0xksy5cl"),
+		"data": cty.StringVal(testSnapshotData),
+	})
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read returned %v", diags)
+	}
+	if hash := d.Get("data_sha256").(string); hash != snapshotDataSha256([]byte(testSnapshotData)) {
+		t.Errorf("read set data_sha256 to %q, expected the hash of the data", hash)
+	}
+}
+
+func TestReadSnapshotDataFile(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "dashboard.sps")
+	if err := os.WriteFile(dataFile, append([]byte("\xef\xbb\xbf"), testSnapshotData...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	content, err := readSnapshotDataFile(dataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != testSnapshotData {
+		t.Errorf("readSnapshotDataFile did not strip the byte order mark")
+	}
+	if _, err := parseSnapshotData(content); err != nil {
+		t.Errorf("parseSnapshotData returned %v for a .sps file", err)
+	}
+
+	if _, err := readSnapshotDataFile(filepath.Join(t.TempDir(), "missing.sps")); err == nil {
+		t.Errorf("readSnapshotDataFile returned no error for a missing file")
+	}
+}
+
 func testAccCheckWorkspaceSnapshotExists(workspaceHandle string) resource.TestCheckFunc {
 	ctx := context.Background()
 	return func(s *terraform.State) error {
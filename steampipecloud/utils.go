@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -27,6 +31,34 @@ func isUserConnection(d *schema.ResourceData) (isUser bool, orgHandle string) {
 	return
 }
 
+// forceNewOnOrganizationChange plans a replacement when a resource moves between the user and organization scope,
+// including when organization is removed from the config, since Steampipe Cloud has no API to transfer a resource.
+// A CustomizeDiff cannot return warnings, so the reason for the replacement is given in the organization argument docs.
+func forceNewOnOrganizationChange(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	oldOrgHandle, _ := d.GetChange("organization")
+	if rawConfig := d.GetRawConfig(); rawConfig.IsKnown() && !rawConfig.IsNull() && rawConfig.GetAttr("organization").IsNull() && oldOrgHandle.(string) != "" {
+		if err := d.SetNew("organization", ""); err != nil {
+			return err
+		}
+	}
+	if !d.HasChange("organization") {
+		return nil
+	}
+	_, newOrgHandle := d.GetChange("organization")
+	log.Printf("[INFO] %s is moving from %s to %s, which will destroy and recreate it", d.Id(), organizationScopeName(oldOrgHandle.(string)), organizationScopeName(newOrgHandle.(string)))
+	return d.ForceNew("organization")
+}
+
+func organizationScopeName(orgHandle string) string {
+	if orgHandle == "" {
+		return "the user account"
+	}
+	return fmt.Sprintf("organization %s", orgHandle)
+}
+
 // helper functions
 func getUserHandler(ctx context.Context, client *SteampipeClient) (string, *http.Response, error) {
 	resp, r, err := client.APIClient.Actors.Get(ctx).Execute()
@@ -60,6 +92,147 @@ func getWorkspaceDetails(ctx context.Context, client *SteampipeClient, d *schema
 	return &resp, r, nil
 }
 
+// listWorkspaceConnections returns all of the connections attached to a workspace. An empty orgHandle
+// refers to a workspace of the user.
+func listWorkspaceConnections(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle string) ([]steampipe.WorkspaceConn, *http.Response, error) {
+	var actorHandle string
+	var r *http.Response
+	var err error
+	if orgHandle == "" {
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return nil, r, err
+		}
+	}
+
+	var items []steampipe.WorkspaceConn
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft {
+		var resp steampipe.ListWorkspaceConnResponse
+		if orgHandle == "" {
+			req := client.APIClient.UserWorkspaceConnectionAssociations.List(ctx, actorHandle, workspaceHandle)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		} else {
+			req := client.APIClient.OrgWorkspaceConnectionAssociations.List(ctx, orgHandle, workspaceHandle)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		}
+		if err != nil {
+			return nil, r, err
+		}
+		items = append(items, resp.GetItems()...)
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	return items, r, nil
+}
+
+// sqlQuote quotes a value as a string literal for the where clause of a list request
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// listWorkspaceSnapshots returns all of the snapshots of a workspace, optionally filtered with a where clause. An
+// empty orgHandle refers to a workspace of the user.
+func listWorkspaceSnapshots(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle, where string) ([]steampipe.WorkspaceSnapshot, *http.Response, error) {
+	var actorHandle string
+	var r *http.Response
+	var err error
+	if orgHandle == "" {
+		actorHandle, r, err = getUserHandler(ctx, client)
+		if err != nil {
+			return nil, r, err
+		}
+	}
+
+	var items []steampipe.WorkspaceSnapshot
+	pagesLeft := true
+	var nextToken string
+	for pagesLeft {
+		var resp steampipe.ListWorkspaceSnapshotsResponse
+		if orgHandle == "" {
+			req := client.APIClient.UserWorkspaceSnapshots.List(ctx, actorHandle, workspaceHandle)
+			if where != "" {
+				req = req.Where(where)
+			}
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		} else {
+			req := client.APIClient.OrgWorkspaceSnapshots.List(ctx, orgHandle, workspaceHandle)
+			if where != "" {
+				req = req.Where(where)
+			}
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, r, err = req.Execute()
+		}
+		if err != nil {
+			return nil, r, err
+		}
+		items = append(items, resp.GetItems()...)
+		nextToken = resp.GetNextToken()
+		pagesLeft = nextToken != ""
+	}
+	return items, r, nil
+}
+
+// deleteWorkspaceSnapshot deletes a snapshot of a workspace. An empty orgHandle refers to a workspace of the user.
+func deleteWorkspaceSnapshot(ctx context.Context, client *SteampipeClient, orgHandle, workspaceHandle, snapshotId string) (*http.Response, error) {
+	if orgHandle == "" {
+		actorHandle, r, err := getUserHandler(ctx, client)
+		if err != nil {
+			return r, err
+		}
+		_, r, err = client.APIClient.UserWorkspaceSnapshots.Delete(ctx, actorHandle, workspaceHandle, snapshotId).Execute()
+		return r, err
+	}
+	_, r, err := client.APIClient.OrgWorkspaceSnapshots.Delete(ctx, orgHandle, workspaceHandle, snapshotId).Execute()
+	return r, err
+}
+
+// The Steampipe Cloud console, used unless the provider is configured with another host
+const defaultConsoleURL = "https://cloud.steampipe.io"
+
+// consoleURL returns the URL of the Steampipe Cloud console for the host the client connects to
+func consoleURL(client *SteampipeClient) string {
+	if client.Config == nil || client.Config.Host == "" {
+		return defaultConsoleURL
+	}
+	parsedURL, err := url.Parse(client.Config.Host)
+	if err != nil || parsedURL.Host == "" {
+		return defaultConsoleURL
+	}
+	return fmt.Sprintf("https://%s", parsedURL.Host)
+}
+
+// isNotFound returns true if the API responded that the requested item does not exist
+func isNotFound(r *http.Response) bool {
+	return r != nil && r.StatusCode == http.StatusNotFound
+}
+
+// resourceNotFound removes a resource that was deleted outside of Terraform from the state, so that it is
+// planned to be created again, and warns about it
+func resourceNotFound(d *schema.ResourceData, summary string) diag.Diagnostics {
+	log.Printf("\n[WARN] %s, removing it from the state", summary)
+	d.SetId("")
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  summary,
+			Detail:   "The resource no longer exists and has been removed from the state.",
+		},
+	}
+}
+
 // Decode response body
 func decodeResponse(r *http.Response) string {
 	var errBody interface{}